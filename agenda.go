@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TaskTrackerCLI/dates"
+	"github.com/TaskTrackerCLI/structures"
+	"github.com/TaskTrackerCLI/task_manager"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var agendaCmd = &cobra.Command{
+	Use:   "agenda",
+	Short: "show open tasks with a due date: overdue, today, this week and later",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		agenda, err := tm.Agenda(time.Now())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error building agenda: %v\n", err)
+			return
+		}
+		if len(agenda) == 0 {
+			fmt.Println("No open tasks with a due date.")
+			return
+		}
+		for _, bucket := range dates.Buckets {
+			tasks := agenda[bucket]
+			if len(tasks) == 0 {
+				continue
+			}
+			fmt.Printf("%s (%d):\n", bucket, len(tasks))
+			table := tablewriter.NewWriter(os.Stdout)
+			table.Header("ID", "Name", "Status", "Priority", "Due")
+			for _, task := range tasks {
+				tableRow := []string{strconv.Itoa(task.TaskId), task.TaskName, task.TaskStatus, task.TaskPriority, formatDue(task)}
+				err := table.Append(tableRow)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error appending row: %v\n", err)
+				}
+			}
+			err := table.Render()
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error rendering table: %v\n", err)
+			}
+		}
+	},
+}
+
+// formatDue - срок для таблицы с правилом повторения, если оно есть
+func formatDue(task structures.Task) string {
+	if task.TaskRecurrence == "" {
+		return formatDueDate(task)
+	}
+	return strings.TrimSpace(formatDueDate(task) + " 🔁 " + task.TaskRecurrence)
+}
+
+// formatDueDate - просроченные и близкие сроки помечаются, у выполненных тасков срок не подсвечивается
+func formatDueDate(task structures.Task) string {
+	now := time.Now()
+	due, ok := task_manager.DueDate(task, now.Location())
+	if !ok {
+		return task.TaskDueDate
+	}
+	if task.TaskStatus == task_manager.StatusDone {
+		return task.TaskDueDate
+	}
+	switch days := dates.DaysUntil(due, now); {
+	case days < 0:
+		return fmt.Sprintf("🔴 %s (overdue %dd)", task.TaskDueDate, -days)
+	case days == 0:
+		return fmt.Sprintf("🟡 %s (today)", task.TaskDueDate)
+	case dates.IsDueSoon(due, now):
+		return fmt.Sprintf("🟡 %s (in %dd)", task.TaskDueDate, days)
+	}
+	return task.TaskDueDate
+}
+
+func init() {
+	mainCmd.AddCommand(agendaCmd)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/TaskTrackerCLI/structures"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var archiveCmd = &cobra.Command{
+	Use:   "archive",
+	Short: "browse archived DONE tasks",
+}
+
+var archiveListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list archived tasks",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		tasks, err := tm.ListArchive()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing archive: %v\n", err)
+			return
+		}
+		if len(tasks) == 0 {
+			fmt.Println("Archive is empty.")
+			return
+		}
+		renderArchive(tasks)
+		fmt.Printf("Archived tasks (Total: %d):\n", len(tasks))
+	},
+}
+
+var archiveSearchCmd = &cobra.Command{
+	Use:   "search [query]",
+	Short: "search archived tasks by query",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		query := args[0]
+		tasks, err := tm.SearchArchive(query)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error searching archive: %v\n", err)
+			return
+		}
+		if len(tasks) == 0 {
+			fmt.Printf("No archived tasks found matching query '%s'.\n", query)
+			return
+		}
+		renderArchive(tasks)
+	},
+}
+
+var unarchiveCmd = &cobra.Command{
+	Use:   "unarchive [task_id]",
+	Short: "return an archived task to the active list",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		taskID, err := strconv.Atoi(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Task ID must be an integer. %v\n", err)
+			return
+		}
+		ok, err := tm.UnarchiveTask(taskID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error unarchiving task: %v\n", err)
+			return
+		}
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: Task with ID %d is not in the archive.\n", taskID)
+			return
+		}
+		fmt.Printf("📤 Task ID %d returned from the archive.\n", taskID)
+	},
+}
+
+func renderArchive(tasks []structures.Task) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header("ID", "Name", "Description", "Status", "Archived")
+	for _, task := range tasks {
+		tableRow := []string{strconv.Itoa(task.TaskId), task.TaskName, task.TaskDescription, task.TaskStatus, task.TaskArchivedAt}
+		err := table.Append(tableRow)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error appending row: %v\n", err)
+		}
+	}
+	err := table.Render()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error rendering table: %v\n", err)
+	}
+}
+
+func init() {
+	archiveCmd.AddCommand(archiveListCmd)
+	archiveCmd.AddCommand(archiveSearchCmd)
+	archiveCmd.AddCommand(unarchiveCmd)
+	mainCmd.AddCommand(archiveCmd)
+}
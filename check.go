@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/TaskTrackerCLI/structures"
+	"github.com/TaskTrackerCLI/task_manager"
+	"github.com/spf13/cobra"
+)
+
+var checkAutoDone bool
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "manage the checklist of a task",
+}
+
+var checkAddCmd = &cobra.Command{
+	Use:   "add [task_id] [item]",
+	Short: "add an item to the end of a task checklist",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		taskID, err := strconv.Atoi(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Task ID must be an integer. %v\n", err)
+			return
+		}
+		ok, err := tm.AddCheckItem(taskID, args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error changing checklist: %v\n", err)
+			return
+		}
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: Task with ID %d not found.\n", taskID)
+			return
+		}
+		fmt.Printf("☑️ Item added to task ID %d.\n", taskID)
+		printTaskChecklist(taskID)
+	},
+}
+
+var checkToggleCmd = &cobra.Command{
+	Use:   "toggle [task_id] [n]",
+	Short: "check or uncheck item n of a task checklist",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		taskID, n, ok := parseCheckArgs(args)
+		if !ok {
+			return
+		}
+		ok, result, err := tm.ToggleCheckItem(taskID, n, checkAutoDone)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error changing checklist: %v\n", err)
+			return
+		}
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: Task with ID %d not found.\n", taskID)
+			return
+		}
+		printTaskChecklist(taskID)
+		printAutoDone(taskID, result)
+	},
+}
+
+var checkRemoveCmd = &cobra.Command{
+	Use:   "remove [task_id] [n]",
+	Short: "remove item n from a task checklist",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		taskID, n, ok := parseCheckArgs(args)
+		if !ok {
+			return
+		}
+		ok, result, err := tm.RemoveCheckItem(taskID, n, checkAutoDone)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error changing checklist: %v\n", err)
+			return
+		}
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: Task with ID %d not found.\n", taskID)
+			return
+		}
+		fmt.Printf("🗑️ Item %d removed from task ID %d.\n", n, taskID)
+		printTaskChecklist(taskID)
+		printAutoDone(taskID, result)
+	},
+}
+
+// parseCheckArgs - id таска и номер пункта чек-листа, false если их не удалось разобрать
+func parseCheckArgs(args []string) (int, int, bool) {
+	taskID, err := strconv.Atoi(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Task ID must be an integer. %v\n", err)
+		return 0, 0, false
+	}
+	n, err := strconv.Atoi(args[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Item number must be an integer. %v\n", err)
+		return 0, 0, false
+	}
+	return taskID, n, true
+}
+
+// printAutoDone - сообщает, переведен ли таск в DONE после изменения чек-листа, или почему нет
+func printAutoDone(taskID int, result task_manager.AutoDone) {
+	if result.Completed {
+		fmt.Printf("🏷️ All items checked, task ID %d marked as %s.\n", taskID, task_manager.StatusDone)
+	}
+	if result.Skipped != nil {
+		fmt.Fprintf(os.Stderr, "⚠️ All items checked, but task ID %d was not marked as %s: %v\n", taskID, task_manager.StatusDone, result.Skipped)
+	}
+}
+
+// printTaskChecklist - печатает чек-лист таска после изменения
+func printTaskChecklist(taskID int) {
+	task, ok, err := tm.GetTask(taskID)
+	if err != nil || !ok {
+		return
+	}
+	printChecklist(task.TaskChecklist)
+	if len(task.TaskChecklist) > 0 {
+		fmt.Printf("Progress: %s\n", formatChecklist(task))
+	}
+}
+
+// printChecklist - пункты чек-листа с номерами, по которым их отмечают и удаляют
+func printChecklist(checklist []structures.ChecklistItem) {
+	for i, item := range checklist {
+		mark := " "
+		if item.Done {
+			mark = "x"
+		}
+		fmt.Printf("  %d. [%s] %s\n", i+1, mark, item.Text)
+	}
+}
+
+// formatChecklist - прогресс чек-листа для таблицы: 3/5, пусто если чек-листа нет
+func formatChecklist(task structures.Task) string {
+	done, total := task_manager.ChecklistProgress(task)
+	if total == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d", done, total)
+}
+
+func init() {
+	checkToggleCmd.Flags().BoolVar(&checkAutoDone, "auto-done", false, "mark the task DONE once all items are checked")
+	checkRemoveCmd.Flags().BoolVar(&checkAutoDone, "auto-done", false, "mark the task DONE once all remaining items are checked")
+
+	checkCmd.AddCommand(checkAddCmd)
+	checkCmd.AddCommand(checkToggleCmd)
+	checkCmd.AddCommand(checkRemoveCmd)
+	mainCmd.AddCommand(checkCmd)
+}
@@ -0,0 +1,83 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var commentCmd = &cobra.Command{
+	Use:   "comment",
+	Short: "add or list task comments",
+}
+
+var commentAddCmd = &cobra.Command{
+	Use:   "add [task_id] [text]",
+	Short: "append a comment to a task",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		taskID, err := strconv.Atoi(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Task ID must be an integer. %v\n", err)
+			return
+		}
+		ok, err := tm.AddComment(taskID, args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error adding comment: %v\n", err)
+			return
+		}
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: Task with ID %d not found.\n", taskID)
+			return
+		}
+		fmt.Printf("💬 Comment added to task ID %d.\n", taskID)
+	},
+}
+
+var commentListCmd = &cobra.Command{
+	Use:   "list [task_id]",
+	Short: "list the comments of a task, oldest first",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		taskID, err := strconv.Atoi(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Task ID must be an integer. %v\n", err)
+			return
+		}
+		comments, ok, err := tm.Comments(taskID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading comments: %v\n", err)
+			return
+		}
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: Task with ID %d not found.\n", taskID)
+			return
+		}
+		if len(comments) == 0 {
+			fmt.Printf("No comments on task ID %d.\n", taskID)
+			return
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.Header("When", "Who", "Comment")
+		for _, comment := range comments {
+			err := table.Append([]string{comment.At, comment.Actor, comment.Text})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error appending row: %v\n", err)
+			}
+		}
+		err = table.Render()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error rendering table: %v\n", err)
+		}
+		fmt.Printf("Comments on task ID %d (Total: %d):\n", taskID, len(comments))
+	},
+}
+
+func init() {
+	commentCmd.AddCommand(commentAddCmd)
+	commentCmd.AddCommand(commentListCmd)
+	mainCmd.AddCommand(commentCmd)
+}
@@ -0,0 +1,179 @@
+package dates
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Layout - формат, в котором срок хранится в таске
+const Layout = "2006-01-02"
+
+// DueSoonDays - за сколько дней до срока таск считается "скоро срок"
+const DueSoonDays = 2
+
+// Bucket - раздел повестки, в который попадает срок
+type Bucket int
+
+const (
+	Overdue Bucket = iota
+	Today
+	ThisWeek
+	Later
+)
+
+// Buckets - разделы повестки в порядке вывода
+var Buckets = []Bucket{Overdue, Today, ThisWeek, Later}
+
+func (bucket Bucket) String() string {
+	switch bucket {
+	case Overdue:
+		return "Overdue"
+	case Today:
+		return "Today"
+	case ThisWeek:
+		return "This week"
+	default:
+		return "Later"
+	}
+}
+
+// Parse - разбирает срок относительно now. Поддерживаются 2026-11-01, today, tomorrow, yesterday,
+// названия дней недели (ближайший такой день после сегодняшнего) и смещения +3d, +2w, +1m, -1d.
+// Возвращает полночь нужного дня в часовом поясе now
+func Parse(value string, now time.Time) (time.Time, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	today := StartOfDay(now)
+
+	switch value {
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	case "yesterday":
+		return today.AddDate(0, 0, -1), nil
+	}
+
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		name := strings.ToLower(weekday.String())
+		if value == name || value == name[:3] {
+			days := (int(weekday) - int(today.Weekday()) + 7) % 7
+			if days == 0 {
+				days = 7
+			}
+			return today.AddDate(0, 0, days), nil
+		}
+	}
+
+	if strings.HasPrefix(value, "+") || strings.HasPrefix(value, "-") {
+		return parseOffset(value, today)
+	}
+
+	date, err := time.ParseInLocation(Layout, value, now.Location())
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %q, use YYYY-MM-DD, today, tomorrow, a weekday or an offset like +3d", value)
+	}
+	return date, nil
+}
+
+// parseOffset - смещение вида +3d, +2w, +1m, -1d от today
+func parseOffset(value string, today time.Time) (time.Time, error) {
+	if len(value) < 3 {
+		return time.Time{}, fmt.Errorf("invalid date offset %q, use e.g. +3d, +2w or +1m", value)
+	}
+	amount, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date offset %q, use e.g. +3d, +2w or +1m", value)
+	}
+	switch value[len(value)-1] {
+	case 'd':
+		return today.AddDate(0, 0, amount), nil
+	case 'w':
+		return today.AddDate(0, 0, 7*amount), nil
+	case 'm':
+		return today.AddDate(0, amount, 0), nil
+	}
+	return time.Time{}, fmt.Errorf("invalid date offset %q, use e.g. +3d, +2w or +1m", value)
+}
+
+// Format - срок в формате хранения
+func Format(date time.Time) string {
+	return date.Format(Layout)
+}
+
+// ParseStored - разбирает срок, сохраненный в формате Layout, в часовом поясе loc
+func ParseStored(value string, loc *time.Location) (time.Time, error) {
+	return time.ParseInLocation(Layout, value, loc)
+}
+
+// StartOfDay - полночь дня t
+func StartOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
+
+// DaysUntil - сколько дней от сегодняшнего дня (по now) до due, отрицательное значение - срок прошел
+func DaysUntil(due, now time.Time) int {
+	today := StartOfDay(now)
+	due = StartOfDay(due.In(now.Location()))
+	// через Date, а не деление длительности, чтобы переход на летнее время не сбивал счет
+	return int(time.Date(due.Year(), due.Month(), due.Day(), 12, 0, 0, 0, time.UTC).
+		Sub(time.Date(today.Year(), today.Month(), today.Day(), 12, 0, 0, 0, time.UTC)).Hours() / 24)
+}
+
+// BucketOf - раздел повестки для срока due: просрочено, сегодня, в ближайшие 7 дней или позже
+func BucketOf(due, now time.Time) Bucket {
+	days := DaysUntil(due, now)
+	switch {
+	case days < 0:
+		return Overdue
+	case days == 0:
+		return Today
+	case days <= 7:
+		return ThisWeek
+	default:
+		return Later
+	}
+}
+
+// IsDueSoon - срок наступает сегодня или в ближайшие DueSoonDays дней
+func IsDueSoon(due, now time.Time) bool {
+	days := DaysUntil(due, now)
+	return days >= 0 && days <= DueSoonDays
+}
+
+// Ago - насколько t раньше now словами: just now, 5 minutes ago, 3 hours ago, 2 days ago, 4 months ago.
+// Время после now записывается как in 3 days
+func Ago(t, now time.Time) string {
+	diff := now.Sub(t)
+	future := diff < 0
+	if future {
+		diff = -diff
+	}
+	const day = 24 * time.Hour
+	var amount int
+	var unit string
+	switch {
+	case diff < time.Minute:
+		return "just now"
+	case diff < time.Hour:
+		amount, unit = int(diff/time.Minute), "minute"
+	case diff < day:
+		amount, unit = int(diff/time.Hour), "hour"
+	case diff < 30*day:
+		amount, unit = int(diff/day), "day"
+	case diff < 365*day:
+		amount, unit = int(diff/(30*day)), "month"
+	default:
+		amount, unit = int(diff/(365*day)), "year"
+	}
+	phrase := fmt.Sprintf("%d %s", amount, unit)
+	if amount != 1 {
+		phrase += "s"
+	}
+	if future {
+		return "in " + phrase
+	}
+	return phrase + " ago"
+}
@@ -0,0 +1,109 @@
+package dates
+
+import (
+	"testing"
+	"time"
+)
+
+// TestParse - проверяет разбор абсолютных дат, ключевых слов, дней недели и смещений.
+func TestParse(t *testing.T) {
+	// четверг
+	now := time.Date(2026, time.October, 15, 18, 30, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		value   string
+		want    string
+		wantErr bool
+	}{
+		{name: "ISO date", value: "2026-11-01", want: "2026-11-01"},
+		{name: "Today", value: "today", want: "2026-10-15"},
+		{name: "Tomorrow ignores case and spaces", value: " Tomorrow ", want: "2026-10-16"},
+		{name: "Yesterday", value: "yesterday", want: "2026-10-14"},
+		{name: "Weekday later this week", value: "saturday", want: "2026-10-17"},
+		{name: "Short weekday", value: "mon", want: "2026-10-19"},
+		{name: "Same weekday means next week", value: "thursday", want: "2026-10-22"},
+		{name: "Days offset", value: "+3d", want: "2026-10-18"},
+		{name: "Weeks offset", value: "+2w", want: "2026-10-29"},
+		{name: "Months offset", value: "+1m", want: "2026-11-15"},
+		{name: "Negative offset", value: "-1d", want: "2026-10-14"},
+		{name: "Unknown unit", value: "+3y", wantErr: true},
+		{name: "Offset without number", value: "+d", wantErr: true},
+		{name: "Garbage", value: "next sprint", wantErr: true},
+		{name: "Invalid day", value: "2026-02-30", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.value, now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if err == nil && Format(got) != tt.want {
+				t.Errorf("Parse(%q) = %s, want %s", tt.value, Format(got), tt.want)
+			}
+		})
+	}
+}
+
+// TestBucketOf - проверяет раскладку сроков по разделам повестки и признак "скоро срок".
+func TestBucketOf(t *testing.T) {
+	now := time.Date(2026, time.October, 15, 23, 59, 0, 0, time.UTC)
+
+	tests := []struct {
+		due         string
+		wantBucket  Bucket
+		wantDueSoon bool
+	}{
+		{due: "2026-10-01", wantBucket: Overdue},
+		{due: "2026-10-14", wantBucket: Overdue},
+		{due: "2026-10-15", wantBucket: Today, wantDueSoon: true},
+		{due: "2026-10-17", wantBucket: ThisWeek, wantDueSoon: true},
+		{due: "2026-10-18", wantBucket: ThisWeek},
+		{due: "2026-10-22", wantBucket: ThisWeek},
+		{due: "2026-10-23", wantBucket: Later},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.due, func(t *testing.T) {
+			due, err := ParseStored(tt.due, time.UTC)
+			if err != nil {
+				t.Fatalf("ParseStored() error = %v", err)
+			}
+			if got := BucketOf(due, now); got != tt.wantBucket {
+				t.Errorf("BucketOf(%s) = %s, want %s", tt.due, got, tt.wantBucket)
+			}
+			if got := IsDueSoon(due, now); got != tt.wantDueSoon {
+				t.Errorf("IsDueSoon(%s) = %v, want %v", tt.due, got, tt.wantDueSoon)
+			}
+		})
+	}
+}
+
+// TestAgo - проверяет запись времени относительно now: единицы, единственное число и будущее время.
+func TestAgo(t *testing.T) {
+	now := time.Date(2026, time.October, 15, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		at   time.Time
+		want string
+	}{
+		{at: now.Add(-30 * time.Second), want: "just now"},
+		{at: now.Add(-time.Minute), want: "1 minute ago"},
+		{at: now.Add(-45 * time.Minute), want: "45 minutes ago"},
+		{at: now.Add(-5 * time.Hour), want: "5 hours ago"},
+		{at: now.AddDate(0, 0, -1), want: "1 day ago"},
+		{at: now.AddDate(0, 0, -3), want: "3 days ago"},
+		{at: now.AddDate(0, 0, -65), want: "2 months ago"},
+		{at: now.AddDate(-2, 0, 0), want: "2 years ago"},
+		{at: now.AddDate(0, 0, 3), want: "in 3 days"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := Ago(tt.at, now); got != tt.want {
+				t.Errorf("Ago(%s) = %q, want %q", tt.at, got, tt.want)
+			}
+		})
+	}
+}
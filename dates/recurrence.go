@@ -0,0 +1,331 @@
+package dates
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Rule - правило повторения таска. Сроки хранятся с точностью до дня, поэтому минуты и часы
+// в cron-выражении проверяются, но на расписание не влияют
+type Rule struct {
+	kind     string
+	interval int
+	weekdays []time.Weekday
+	monthDay int
+	cron     *cronSpec
+}
+
+const (
+	ruleDaily   = "daily"
+	ruleWeekly  = "weekly"
+	ruleMonthly = "monthly"
+	ruleEvery   = "every"
+	ruleCron    = "cron"
+)
+
+// maxCronSearchDays - насколько далеко ищется следующий подходящий под cron день
+const maxCronSearchDays = 8 * 366
+
+const ruleHelp = "use daily, weekly, weekly:mon,thu, monthly, monthly:15, every:3d, every:2w or cron:\"0 9 * * 1-5\""
+
+// ParseRule - разбирает правило повторения: daily, weekly (каждые 7 дней), weekly:mon,thu,
+// monthly (тот же день месяца, см. AnchoredTo), monthly:15, every:3d, every:2w и cron:"мин час день месяц день_недели"
+func ParseRule(value string) (Rule, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	kind, argument, found := strings.Cut(value, ":")
+	if !found {
+		// every 3d, cron 0 9 * * 1
+		kind, argument, _ = strings.Cut(value, " ")
+	}
+	kind, argument = strings.TrimSpace(kind), strings.TrimSpace(argument)
+
+	switch kind {
+	case ruleDaily:
+		if argument == "" {
+			return Rule{kind: ruleEvery, interval: 1}, nil
+		}
+	case ruleWeekly:
+		if argument == "" {
+			return Rule{kind: ruleEvery, interval: 7}, nil
+		}
+		weekdays, err := parseWeekdays(argument)
+		if err != nil {
+			return Rule{}, err
+		}
+		return Rule{kind: ruleWeekly, weekdays: weekdays}, nil
+	case ruleMonthly:
+		if argument == "" {
+			return Rule{kind: ruleMonthly}, nil
+		}
+		day, err := strconv.Atoi(argument)
+		if err != nil || day < 1 || day > 31 {
+			return Rule{}, fmt.Errorf("invalid day of month %q in %q, use 1-31", argument, value)
+		}
+		return Rule{kind: ruleMonthly, monthDay: day}, nil
+	case ruleEvery:
+		days, err := parseInterval(argument)
+		if err != nil {
+			return Rule{}, err
+		}
+		return Rule{kind: ruleEvery, interval: days}, nil
+	case ruleCron:
+		spec, err := parseCron(strings.Trim(argument, `"'`))
+		if err != nil {
+			return Rule{}, err
+		}
+		return Rule{kind: ruleCron, cron: spec}, nil
+	}
+	return Rule{}, fmt.Errorf("invalid recurrence %q, %s", value, ruleHelp)
+}
+
+// String - правило в каноническом виде, в котором оно хранится в таске
+func (rule Rule) String() string {
+	switch rule.kind {
+	case ruleWeekly:
+		names := make([]string, len(rule.weekdays))
+		for i, weekday := range rule.weekdays {
+			names[i] = strings.ToLower(weekday.String()[:3])
+		}
+		return ruleWeekly + ":" + strings.Join(names, ",")
+	case ruleMonthly:
+		if rule.monthDay == 0 {
+			return ruleMonthly
+		}
+		return fmt.Sprintf("%s:%d", ruleMonthly, rule.monthDay)
+	case ruleEvery:
+		switch {
+		case rule.interval == 1:
+			return ruleDaily
+		case rule.interval == 7:
+			return ruleWeekly
+		case rule.interval%7 == 0:
+			return fmt.Sprintf("%s:%dw", ruleEvery, rule.interval/7)
+		}
+		return fmt.Sprintf("%s:%dd", ruleEvery, rule.interval)
+	case ruleCron:
+		return ruleCron + ":" + rule.cron.source
+	}
+	return ""
+}
+
+// AnchoredTo - для monthly без дня закрепляет день месяца первого срока серии day: monthly у таска
+// со сроком 31 января превращается в monthly:31, и после февраля серия возвращается на 31-е.
+// Остальные правила не меняются
+func (rule Rule) AnchoredTo(day time.Time) Rule {
+	if rule.kind == ruleMonthly && rule.monthDay == 0 {
+		rule.monthDay = day.Day()
+	}
+	return rule
+}
+
+// Next - первый день после after, подходящий под правило, в часовом поясе after.
+// false, если такого дня нет (например, cron на 30 февраля)
+func (rule Rule) Next(after time.Time) (time.Time, bool) {
+	day := StartOfDay(after)
+	switch rule.kind {
+	case ruleEvery:
+		return day.AddDate(0, 0, rule.interval), true
+	case ruleMonthly:
+		monthDay := rule.monthDay
+		if monthDay == 0 {
+			// правило без закрепленного дня (см. AnchoredTo) считается от after и сползает после коротких месяцев
+			monthDay = day.Day()
+		}
+		for months := 0; months <= 12; months++ {
+			first := time.Date(day.Year(), day.Month()+time.Month(months), 1, 0, 0, 0, 0, day.Location())
+			// в коротких месяцах берется последний день
+			target := daysIn(first)
+			if monthDay < target {
+				target = monthDay
+			}
+			candidate := first.AddDate(0, 0, target-1)
+			if candidate.After(day) {
+				return candidate, true
+			}
+		}
+	case ruleWeekly:
+		for days := 1; days <= 7; days++ {
+			candidate := day.AddDate(0, 0, days)
+			if containsWeekday(rule.weekdays, candidate.Weekday()) {
+				return candidate, true
+			}
+		}
+	case ruleCron:
+		for days := 1; days <= maxCronSearchDays; days++ {
+			candidate := day.AddDate(0, 0, days)
+			if rule.cron.matches(candidate) {
+				return candidate, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+// First - первый день не раньше from, подходящий под правило. Для интервальных правил и monthly без дня
+// это сам from: с него начинается отсчет
+func (rule Rule) First(from time.Time) (time.Time, bool) {
+	day := StartOfDay(from)
+	if rule.kind == ruleEvery || (rule.kind == ruleMonthly && rule.monthDay == 0) {
+		return day, true
+	}
+	return rule.Next(day.AddDate(0, 0, -1))
+}
+
+// daysIn - количество дней в месяце, которому принадлежит date
+func daysIn(date time.Time) int {
+	return time.Date(date.Year(), date.Month()+1, 0, 0, 0, 0, 0, date.Location()).Day()
+}
+
+func parseInterval(value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if len(value) < 2 {
+		return 0, fmt.Errorf("invalid interval %q, use e.g. every:3d or every:2w", value)
+	}
+	amount, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || amount < 1 {
+		return 0, fmt.Errorf("invalid interval %q, use e.g. every:3d or every:2w", value)
+	}
+	switch value[len(value)-1] {
+	case 'd':
+		return amount, nil
+	case 'w':
+		return 7 * amount, nil
+	}
+	return 0, fmt.Errorf("invalid interval %q, use e.g. every:3d or every:2w", value)
+}
+
+// parseWeekdays - дни недели через запятую: mon, monday и т.д.
+func parseWeekdays(value string) ([]time.Weekday, error) {
+	var weekdays []time.Weekday
+	for _, name := range strings.Split(value, ",") {
+		weekday, ok := weekdayByName(strings.TrimSpace(name))
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday %q, use mon, tue, wed, thu, fri, sat or sun", name)
+		}
+		if !containsWeekday(weekdays, weekday) {
+			weekdays = append(weekdays, weekday)
+		}
+	}
+	// неделя в правилах начинается с понедельника
+	sort.Slice(weekdays, func(i, j int) bool {
+		return (weekdays[i]+6)%7 < (weekdays[j]+6)%7
+	})
+	return weekdays, nil
+}
+
+func weekdayByName(name string) (time.Weekday, bool) {
+	for weekday := time.Sunday; weekday <= time.Saturday; weekday++ {
+		full := strings.ToLower(weekday.String())
+		if name == full || name == full[:3] {
+			return weekday, true
+		}
+	}
+	return 0, false
+}
+
+func containsWeekday(weekdays []time.Weekday, weekday time.Weekday) bool {
+	for _, candidate := range weekdays {
+		if candidate == weekday {
+			return true
+		}
+	}
+	return false
+}
+
+// cronSpec - разобранное cron-выражение. Для дней хранятся допустимые значения каждого поля
+type cronSpec struct {
+	source                  string
+	monthDays               map[int]bool
+	months                  map[int]bool
+	weekdays                map[int]bool
+	anyMonthDay, anyWeekday bool
+}
+
+// parseCron - пять полей cron: минуты, часы, день месяца, месяц, день недели (0 и 7 - воскресенье).
+// Поле задается как *, число, диапазон 1-5, шаг */2 или 1-15/2 и списки через запятую
+func parseCron(value string) (*cronSpec, error) {
+	fields := strings.Fields(value)
+	if len(fields) != 5 {
+		return nil, fmt.Errorf("invalid cron expression %q, want 5 fields: minute hour day month weekday", value)
+	}
+	limits := [5][2]int{{0, 59}, {0, 23}, {1, 31}, {1, 12}, {0, 7}}
+	sets := make([]map[int]bool, 5)
+	for i, field := range fields {
+		set, err := parseCronField(field, limits[i][0], limits[i][1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid cron expression %q: %w", value, err)
+		}
+		sets[i] = set
+	}
+	if sets[4][7] {
+		sets[4][0] = true
+	}
+	return &cronSpec{
+		source:      strings.Join(fields, " "),
+		monthDays:   sets[2],
+		months:      sets[3],
+		weekdays:    sets[4],
+		anyMonthDay: strings.HasPrefix(fields[2], "*"),
+		anyWeekday:  strings.HasPrefix(fields[4], "*"),
+	}, nil
+}
+
+func parseCronField(field string, low, high int) (map[int]bool, error) {
+	set := make(map[int]bool)
+	for _, part := range strings.Split(field, ",") {
+		rangePart, stepPart, hasStep := strings.Cut(part, "/")
+		step := 1
+		if hasStep {
+			var err error
+			step, err = strconv.Atoi(stepPart)
+			if err != nil || step < 1 {
+				return nil, fmt.Errorf("invalid step in %q", part)
+			}
+		}
+		from, to := low, high
+		if rangePart != "*" {
+			first, last, isRange := strings.Cut(rangePart, "-")
+			var err error
+			if from, err = strconv.Atoi(first); err != nil {
+				return nil, fmt.Errorf("invalid value %q", part)
+			}
+			to = from
+			if isRange {
+				if to, err = strconv.Atoi(last); err != nil {
+					return nil, fmt.Errorf("invalid value %q", part)
+				}
+			} else if hasStep {
+				to = high
+			}
+		}
+		if from < low || to > high || from > to {
+			return nil, fmt.Errorf("value %q out of range %d-%d", part, low, high)
+		}
+		for value := from; value <= to; value += step {
+			set[value] = true
+		}
+	}
+	return set, nil
+}
+
+// matches - подходит ли день под выражение. Как в cron, если ограничены и день месяца, и день недели,
+// достаточно совпадения одного из них
+func (spec *cronSpec) matches(day time.Time) bool {
+	if !spec.months[int(day.Month())] {
+		return false
+	}
+	monthDay := spec.monthDays[day.Day()]
+	weekday := spec.weekdays[int(day.Weekday())]
+	switch {
+	case spec.anyMonthDay && spec.anyWeekday:
+		return true
+	case spec.anyMonthDay:
+		return weekday
+	case spec.anyWeekday:
+		return monthDay
+	}
+	return monthDay || weekday
+}
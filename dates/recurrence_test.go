@@ -0,0 +1,105 @@
+package dates
+
+import (
+	"testing"
+	"time"
+)
+
+// TestRule - проверяет разбор правил повторения, их канонический вид, расчет первой и следующей даты.
+func TestRule(t *testing.T) {
+	// четверг
+	after := time.Date(2026, time.October, 15, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name       string
+		value      string
+		after      time.Time
+		wantString string
+		wantNext   string
+		wantErr    bool
+	}{
+		{name: "Daily", value: "daily", wantString: "daily", wantNext: "2026-10-16"},
+		{name: "Weekly keeps the weekday", value: " Weekly ", wantString: "weekly", wantNext: "2026-10-22"},
+		{name: "Weekly on weekdays", value: "weekly:thu,mon,monday", wantString: "weekly:mon,thu", wantNext: "2026-10-19"},
+		{name: "Weekly on sunday", value: "weekly:sun", wantString: "weekly:sun", wantNext: "2026-10-18"},
+		{name: "Monthly keeps the day", value: "monthly", wantString: "monthly", wantNext: "2026-11-15"},
+		{name: "Monthly on a day", value: "monthly:1", wantString: "monthly:1", wantNext: "2026-11-01"},
+		{name: "Monthly later this month", value: "monthly:20", wantString: "monthly:20", wantNext: "2026-10-20"},
+		{name: "Monthly clamps short months", value: "monthly:31", after: time.Date(2027, time.January, 31, 0, 0, 0, 0, time.UTC), wantString: "monthly:31", wantNext: "2027-02-28"},
+		{name: "Every N days", value: "every 3d", wantString: "every:3d", wantNext: "2026-10-18"},
+		{name: "Every N weeks", value: "every:2w", wantString: "every:2w", wantNext: "2026-10-29"},
+		{name: "Every 7 days is weekly", value: "every:7d", wantString: "weekly", wantNext: "2026-10-22"},
+		{name: "Cron on weekdays", value: `cron:"0 9 * * 1-5"`, after: time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC), wantString: "cron:0 9 * * 1-5", wantNext: "2026-10-19"},
+		{name: "Cron first of quarter", value: "cron:0 0 1 */3 *", wantString: "cron:0 0 1 */3 *", wantNext: "2027-01-01"},
+		{name: "Cron day or weekday", value: "cron:0 0 20 * 0", wantString: "cron:0 0 20 * 0", wantNext: "2026-10-18"},
+		{name: "Cron sunday as 7", value: "cron:0 0 * * 7", wantString: "cron:0 0 * * 7", wantNext: "2026-10-18"},
+		{name: "Cron wrong field count", value: "cron:0 9 * *", wantErr: true},
+		{name: "Cron out of range", value: "cron:0 25 * * *", wantErr: true},
+		{name: "Unknown weekday", value: "weekly:someday", wantErr: true},
+		{name: "Invalid month day", value: "monthly:32", wantErr: true},
+		{name: "Zero interval", value: "every:0d", wantErr: true},
+		{name: "Unknown rule", value: "yearly", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule, err := ParseRule(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseRule(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got := rule.String(); got != tt.wantString {
+				t.Errorf("String() = %q, want %q", got, tt.wantString)
+			}
+			if reparsed, err := ParseRule(rule.String()); err != nil || reparsed.String() != tt.wantString {
+				t.Errorf("canonical form %q does not parse back: %v", rule.String(), err)
+			}
+			from := after
+			if !tt.after.IsZero() {
+				from = tt.after
+			}
+			next, ok := rule.Next(from)
+			if !ok || Format(next) != tt.wantNext {
+				t.Errorf("Next(%s) = %s, %v, want %s", Format(from), Format(next), ok, tt.wantNext)
+			}
+		})
+	}
+
+	for value, want := range map[string]string{"weekly": "2026-10-15", "monthly": "2026-10-15", "weekly:thu": "2026-10-15", "weekly:mon": "2026-10-19", "monthly:1": "2026-11-01"} {
+		rule, err := ParseRule(value)
+		if err != nil {
+			t.Fatalf("ParseRule(%q) error = %v", value, err)
+		}
+		if first, ok := rule.First(after); !ok || Format(first) != want {
+			t.Errorf("First() for %s = %s, %v, want %s", value, Format(first), ok, want)
+		}
+	}
+
+	// monthly, закрепленный за 31 января, после февраля возвращается на 31-е
+	monthly, err := ParseRule("monthly")
+	if err != nil {
+		t.Fatalf("ParseRule() error = %v", err)
+	}
+	anchored := monthly.AnchoredTo(time.Date(2027, time.January, 31, 0, 0, 0, 0, time.UTC))
+	if anchored.String() != "monthly:31" {
+		t.Errorf("AnchoredTo() = %q, want monthly:31", anchored.String())
+	}
+	due := time.Date(2027, time.January, 31, 0, 0, 0, 0, time.UTC)
+	for _, want := range []string{"2027-02-28", "2027-03-31", "2027-04-30", "2027-05-31"} {
+		next, ok := anchored.Next(due)
+		if !ok || Format(next) != want {
+			t.Errorf("Next(%s) = %s, %v, want %s", Format(due), Format(next), ok, want)
+		}
+		due = next
+	}
+
+	rule, err := ParseRule("cron:0 0 30 2 *")
+	if err != nil {
+		t.Fatalf("ParseRule() error = %v", err)
+	}
+	if next, ok := rule.Next(after); ok {
+		t.Errorf("Next() for February 30 = %s, want no date", Format(next))
+	}
+}
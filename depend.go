@@ -0,0 +1,85 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+var dependCmd = &cobra.Command{
+	Use:   "depend",
+	Short: "manage dependencies between tasks",
+}
+
+var dependAddCmd = &cobra.Command{
+	Use:   "add [task_id] [blocker_id]",
+	Short: "mark a task as blocked by another task",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		changeDependency(args, "is now blocked by", tm.AddDependency)
+	},
+}
+
+var dependRemoveCmd = &cobra.Command{
+	Use:   "remove [task_id] [blocker_id]",
+	Short: "remove a dependency between tasks",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		changeDependency(args, "is no longer blocked by", tm.RemoveDependency)
+	},
+}
+
+func changeDependency(args []string, action string, change func(id, blockerId int) (bool, error)) {
+	taskID, err := strconv.Atoi(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Task ID must be an integer. %v\n", err)
+		return
+	}
+	blockerID, err := strconv.Atoi(args[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Blocker ID must be an integer. %v\n", err)
+		return
+	}
+	ok, err := change(taskID, blockerID)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error changing dependencies: %v\n", err)
+		return
+	}
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: Task with ID %d or %d not found.\n", taskID, blockerID)
+		return
+	}
+	fmt.Printf("🔗 Task ID %d %s task ID %d.\n", taskID, action, blockerID)
+}
+
+// formatStatus - статус для таблицы с пометкой, каких тасков ждет заблокированный таск
+func formatStatus(status string, blockers []int) string {
+	if len(blockers) == 0 {
+		return status
+	}
+	return fmt.Sprintf("%s ⛔ %s", status, blockedBy(blockers))
+}
+
+func blockedBy(blockers []int) string {
+	ids := make([]string, len(blockers))
+	for i, id := range blockers {
+		ids[i] = strconv.Itoa(id)
+	}
+	return "blocked by " + strings.Join(ids, ", ")
+}
+
+// warnIfBlocked - предупреждает, что взятый в работу таск еще ждет другие таски
+func warnIfBlocked(taskID int, blockers []int) {
+	if len(blockers) > 0 {
+		fmt.Fprintf(os.Stderr, "⚠️ Warning: task ID %d is %s.\n", taskID, blockedBy(blockers))
+	}
+}
+
+func init() {
+	dependCmd.AddCommand(dependAddCmd)
+	dependCmd.AddCommand(dependRemoveCmd)
+	mainCmd.AddCommand(dependCmd)
+}
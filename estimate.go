@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/TaskTrackerCLI/task_manager"
+	"github.com/spf13/cobra"
+)
+
+var estimatesCmd = &cobra.Command{
+	Use:   "estimates",
+	Short: "compare estimates of DONE tasks with the time between IN_PROGRESS and DONE",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		report, err := tm.EstimateReport()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error building estimates report: %v\n", err)
+			return
+		}
+		if len(report.Tasks) == 0 {
+			fmt.Println("No DONE tasks with an estimate and a recorded IN_PROGRESS start.")
+		} else {
+			renderTimeTable([]string{"ID", "Task", "Estimate", "Actual", "Accuracy"}, len(report.Tasks), func(i int) []string {
+				row := report.Tasks[i]
+				return []string{strconv.Itoa(row.Task.TaskId), row.Task.TaskName, row.Estimate.String(), formatDuration(row.Actual), formatAccuracy(row)}
+			})
+			if report.Estimated > 0 {
+				fmt.Printf("Time estimates: %s estimated, %s actual, overall %s, off by %.0f%% per task on average.\n",
+					formatDuration(report.Estimated), formatDuration(report.Actual), formatRatio(report.Ratio()), report.MeanError()*100)
+			}
+			if report.Points > 0 {
+				fmt.Printf("Story points: %s done in %s, %s per point.\n",
+					strconv.FormatFloat(report.Points, 'f', -1, 64), formatDuration(report.PointsActual), formatDuration(report.TimePerPoint()))
+			}
+		}
+		if report.Unmeasured > 0 {
+			fmt.Printf("%d estimated DONE tasks were never IN_PROGRESS and are not included.\n", report.Unmeasured)
+		}
+	},
+}
+
+// formatAccuracy - точность оценки таска: доля факта от оценки во времени или время на один story point
+func formatAccuracy(row task_manager.EstimateAccuracy) string {
+	if row.Estimate.IsPoints() {
+		return formatDuration(row.TimePerPoint()) + "/pt"
+	}
+	return formatRatio(row.Ratio())
+}
+
+// formatRatio - отношение факта к оценке: 100% - точно, 150% (over) - потрачено больше, чем оценено
+func formatRatio(ratio float64) string {
+	percent := ratio * 100
+	switch {
+	case percent >= 110:
+		return fmt.Sprintf("%.0f%% (over)", percent)
+	case percent <= 90:
+		return fmt.Sprintf("%.0f%% (under)", percent)
+	}
+	return fmt.Sprintf("%.0f%%", percent)
+}
+
+func init() {
+	mainCmd.AddCommand(estimatesCmd)
+}
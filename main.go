@@ -2,10 +2,12 @@ package main
 
 import (
 	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/TaskTrackerCLI/structures"
 	"github.com/TaskTrackerCLI/task_manager"
@@ -15,14 +17,88 @@ import (
 
 var tm *task_manager.TaskManager
 
+var (
+	storageKind  string
+	storagePath  string
+	lockTimeout  time.Duration
+	workflowPath string
+)
+
+var (
+	addPriority    string
+	addDue         string
+	updatePriority string
+	updateDue      string
+	listPriority   string
+	addTags        []string
+	listTags       string
+	listAnyTag     bool
+	addProject     string
+	updateProject  string
+	listProject    string
+	addParent      string
+	updateParent   string
+	markCascade    bool
+	listReady      bool
+	addRecur       string
+	updateRecur    string
+	listRecurring  bool
+	addEstimate    string
+	updateEstimate string
+)
+
 var mainCmd = &cobra.Command{
 	Use:   "TaskTracker",
 	Short: "TaskTracker for track your tasks",
 	Long:  "A little bit long description for TaskTracker",
+	// ошибки хранилища не связаны с аргументами, usage в этом случае только мешает
+	SilenceUsage: true,
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		store, err := openStore(storageKind, storagePath)
+		if err != nil {
+			return err
+		}
+		tm, err = task_manager.NewTaskManagerWithStore(store)
+		if err != nil {
+			return fmt.Errorf("error creating task manager: %w", err)
+		}
+		tm.LockTimeout = lockTimeout
+		tm.Workflow, err = task_manager.LoadWorkflow(workflowPath)
+		if err != nil {
+			return err
+		}
+		return nil
+	},
+	PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
+		return tm.Close()
+	},
 	Run: func(cmd *cobra.Command, args []string) {
 		fmt.Println("Welcome to the TaskTracker CLI! Use --help for usage ")
 	}}
 
+// openStore - создает хранилище выбранного типа, пустой path означает файл по умолчанию
+func openStore(kind, path string) (task_manager.Store, error) {
+	switch strings.ToLower(kind) {
+	case "json":
+		if path == "" {
+			path = "tasks.json"
+		}
+		return task_manager.NewJSONFileStore(path), nil
+	case "journal":
+		if path == "" {
+			path = "tasks.json"
+		}
+		return task_manager.NewJournalStore(path), nil
+	case "sqlite":
+		if path == "" {
+			path = "tasks.db"
+		}
+		return task_manager.NewSQLiteStore(path), nil
+	default:
+		return nil, fmt.Errorf("unknown storage '%s', use json, journal or sqlite", kind)
+	}
+}
+
 var addCmd = &cobra.Command{
 	Use:   "add [task_name] [task_description]",
 	Short: "add a new task",
@@ -30,8 +106,30 @@ var addCmd = &cobra.Command{
 	Run: func(cmd *cobra.Command, args []string) {
 		taskName := args[0]
 		taskDescription := args[1]
+		values := make(map[string]string)
+		if addPriority != "" {
+			values["task_priority"] = addPriority
+		}
+		if addDue != "" {
+			values["task_due"] = addDue
+		}
+		if len(addTags) > 0 {
+			values["task_tags"] = strings.Join(addTags, ",")
+		}
+		if addProject != "" {
+			values["task_project"] = addProject
+		}
+		if addRecur != "" {
+			values["task_recur"] = addRecur
+		}
+		if addEstimate != "" {
+			values["task_estimate"] = addEstimate
+		}
+		if addParent != "" {
+			values["task_parent"] = addParent
+		}
 		fmt.Printf("Adding task: Name='%s', Description='%s'\n", taskName, taskDescription)
-		value, err := tm.AddTask(taskName, taskDescription)
+		value, err := tm.AddTaskWithValues(taskName, taskDescription, values)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error adding task: %v\n", err)
 			return
@@ -43,7 +141,7 @@ var addCmd = &cobra.Command{
 var updateCmd = &cobra.Command{
 	Use:   "update [task_id] [task_name] [task_description]",
 	Short: "update a task",
-	Args:  cobra.ExactArgs(3),
+	Args:  cobra.RangeArgs(1, 3),
 	Run: func(cmd *cobra.Command, args []string) {
 		id, err := strconv.Atoi(args[0])
 		if err != nil {
@@ -51,11 +149,35 @@ var updateCmd = &cobra.Command{
 
 			return
 		}
-		taskName := args[1]
-		taskDescription := args[2]
 		arguments := make(map[string]string)
-		arguments["task_name"] = taskName // 💡
-		arguments["task_description"] = taskDescription
+		if len(args) > 1 {
+			arguments["task_name"] = args[1] // 💡
+		}
+		if len(args) > 2 {
+			arguments["task_description"] = args[2]
+		}
+		if cmd.Flags().Changed("priority") {
+			arguments["task_priority"] = updatePriority
+		}
+		if cmd.Flags().Changed("due") {
+			arguments["task_due"] = updateDue
+		}
+		if cmd.Flags().Changed("project") {
+			arguments["task_project"] = updateProject
+		}
+		if cmd.Flags().Changed("parent") {
+			arguments["task_parent"] = updateParent
+		}
+		if cmd.Flags().Changed("recur") {
+			arguments["task_recur"] = updateRecur
+		}
+		if cmd.Flags().Changed("estimate") {
+			arguments["task_estimate"] = updateEstimate
+		}
+		if len(arguments) == 0 {
+			fmt.Fprintln(os.Stderr, "Error: Nothing to update, pass a new name, description, --priority, --due, --recur, --estimate, --project or --parent.")
+			return
+		}
 
 		updated, err := tm.UpdateTask(id, arguments)
 
@@ -95,36 +217,34 @@ var deleteCmd = &cobra.Command{
 
 			return
 		}
-		fmt.Printf("🗑️ Task ID %d deleted successfully.\n", taskID)
+		fmt.Printf("🗑️ Task ID %d moved to trash (restore with 'trash restore %d').\n", taskID, taskID)
 	},
 }
 
 var markTaskCmd = &cobra.Command{
 	Use:   "mark [task_id] [task_status]",
-	Short: "Mark a task status (TODO, IN_PROGRESS, DONE)",
+	Short: "Mark a task status (TODO, IN_PROGRESS, DONE or any status from the workflow)",
 	Args:  cobra.ExactArgs(2),
 	Run: func(cmd *cobra.Command, args []string) {
-		taskStatus := args[1]
 		taskID, err := strconv.Atoi(args[0])
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error: Invalid Task ID format: %v\n", err)
 			return
 		}
 
+		status := task_manager.NormalizeStatus(args[1])
 		var ok bool
+		var change task_manager.StatusChange
+		if status == task_manager.StatusDone && markCascade {
+			ok, change.NextOccurrence, err = tm.MarkTaskTreeAsDone(taskID)
+		} else {
+			ok, change, err = tm.SetStatus(taskID, status)
+		}
 
-		switch strings.ToUpper(taskStatus) {
-		case "TODO":
-			ok, err = tm.MarkTaskAsTodo(taskID)
-		case "IN_PROGRESS":
-			ok, err = tm.MarkTaskAsInProgress(taskID)
-		case "DONE":
-			ok, err = tm.MarkTaskAsDone(taskID)
-		default:
-			fmt.Fprintf(os.Stderr, "Error: Invalid task status '%s'. Must be TODO, IN_PROGRESS, or DONE.\n", taskStatus)
+		if errors.Is(err, task_manager.ErrOpenSubtasks) {
+			fmt.Fprintf(os.Stderr, "Error marking task ID %d: %v. Finish them first or use --cascade.\n", taskID, err)
 			return
 		}
-
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error marking task ID %d: %v\n", taskID, err)
 			return
@@ -134,45 +254,95 @@ var markTaskCmd = &cobra.Command{
 			return
 		}
 
-		fmt.Printf("🏷️ Task ID %d successfully marked as %s.\n", taskID, strings.ToUpper(taskStatus))
+		fmt.Printf("🏷️ Task ID %d successfully marked as %s.\n", taskID, status)
+		warnIfBlocked(taskID, change.Blockers)
+		if change.NextOccurrence != 0 {
+			if next, ok, err := tm.GetTask(change.NextOccurrence); err == nil && ok {
+				fmt.Printf("🔁 Next occurrence ID %d (%s) added, due %s.\n", next.TaskId, next.TaskRecurrence, next.TaskDueDate)
+			}
+		}
 	},
 }
 
 var listTasksCmd = &cobra.Command{
 	Use:   "list [status]",
-	Short: "list tasks with different status (e.g., done, todo, all)",
+	Short: "list tasks with different status (e.g., done, todo, all or a workflow status), most important first",
 	Args:  cobra.MaximumNArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		status := "ALL"
 		if len(args) == 1 {
-			status = args[0]
+			status = task_manager.NormalizeStatus(args[0])
 		}
 
 		var tasks []structures.Task
+		var err error
 
-		switch status {
-		case "ALL":
-			tasks = tm.ListAllTasks()
-		case "DONE":
-			tasks = tm.ListDoneTasks()
-		case "TODO":
-			tasks = tm.ListTodoTasks()
-		case "IN_PROGRESS":
-			tasks = tm.ListInProgressTasks()
+		switch {
+		case listReady && status != "ALL" && status != task_manager.StatusTodo:
+			fmt.Fprintln(os.Stderr, "Error: --ready only lists TODO tasks.")
+			return
+		case listReady:
+			status = "ready"
+			tasks, err = tm.ListReadyTasks()
+		case status == "ALL":
+			tasks, err = tm.ListAllTasks()
 		default:
-			fmt.Fprintf(os.Stderr, "Error: Invalid status '%s'. Use all, done, todo, or in_progress.\n", status)
+			tasks, err = tm.ListTasksByStatus(status)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing tasks: %v\n", err)
+			return
+		}
+		if listPriority != "" {
+			priority, err := task_manager.ParsePriority(listPriority)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return
+			}
+			tasks = task_manager.FilterByPriority(tasks, priority)
+		}
+		if listTags != "" {
+			tags, err := task_manager.ParseTags(listTags)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return
+			}
+			tasks = task_manager.FilterByTags(tasks, tags, !listAnyTag)
+		}
+		if listRecurring {
+			tasks = task_manager.FilterRecurring(tasks)
+		}
+		if listProject != "" {
+			projectId := 0
+			if !strings.EqualFold(listProject, "none") {
+				project, ok, err := tm.FindProject(listProject)
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "Error reading projects: %v\n", err)
+					return
+				}
+				if !ok {
+					fmt.Fprintf(os.Stderr, "Error: Project '%s' not found.\n", listProject)
+					return
+				}
+				projectId = project.ProjectId
+			}
+			tasks = task_manager.FilterByProject(tasks, projectId)
+		}
+		blockers, err := tm.Blockers()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error checking dependencies: %v\n", err)
 			return
 		}
 		table := tablewriter.NewWriter(os.Stdout)
-		table.Header("ID", "Name", "Description", "Status", "Created", "Updated")
+		table.Header("ID", "Name", "Description", "Status", "Priority", "Due", "Estimate", "Checklist", "Tags", "Created", "Updated")
 		for _, task := range tasks {
-			tableRow := []string{strconv.Itoa(task.TaskId), task.TaskName, task.TaskDescription, task.TaskStatus, task.TaskCreatedAt, task.TaskUpdatedAt}
+			tableRow := []string{strconv.Itoa(task.TaskId), task.TaskName, task.TaskDescription, formatStatus(task.TaskStatus, blockers[task.TaskId]), task.TaskPriority, formatDue(task), task.TaskEstimate, formatChecklist(task), strings.Join(task.TaskTags, ", "), task.TaskCreatedAt, task.TaskUpdatedAt}
 			err := table.Append(tableRow)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error appending row: %v\n", err)
 			}
 		}
-		err := table.Render()
+		err = table.Render()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error rendering table: %v\n", err)
 		}
@@ -187,21 +357,30 @@ var searchCmd = &cobra.Command{
 	Args:  cobra.ExactArgs(1),
 	Run: func(cmd *cobra.Command, args []string) {
 		query := args[0]
-		tasks := tm.SearchTasks(query)
+		tasks, err := tm.SearchTasks(query)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error searching tasks: %v\n", err)
+			return
+		}
 		if len(tasks) == 0 {
 			fmt.Printf("No tasks found matching query '%s'.\n", query)
 			return
 		}
+		blockers, err := tm.Blockers()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error checking dependencies: %v\n", err)
+			return
+		}
 		table := tablewriter.NewWriter(os.Stdout)
-		table.Header("ID", "Name", "Description", "Status", "Created", "Updated")
+		table.Header("ID", "Name", "Description", "Status", "Priority", "Due", "Estimate", "Checklist", "Tags", "Created", "Updated")
 		for _, task := range tasks {
-			tableRow := []string{strconv.Itoa(task.TaskId), task.TaskName, task.TaskDescription, task.TaskStatus, task.TaskCreatedAt, task.TaskUpdatedAt}
+			tableRow := []string{strconv.Itoa(task.TaskId), task.TaskName, task.TaskDescription, formatStatus(task.TaskStatus, blockers[task.TaskId]), task.TaskPriority, formatDue(task), task.TaskEstimate, formatChecklist(task), strings.Join(task.TaskTags, ", "), task.TaskCreatedAt, task.TaskUpdatedAt}
 			err := table.Append(tableRow)
 			if err != nil {
 				fmt.Fprintf(os.Stderr, "Error appending row: %v\n", err)
 			}
 		}
-		err := table.Render()
+		err = table.Render()
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error rendering table: %v\n", err)
 		}
@@ -211,10 +390,10 @@ var searchCmd = &cobra.Command{
 // main.go (Улучшенная версия)
 var cleanCmd = &cobra.Command{
 	Use:   "clean",
-	Short: "Clean tasks with DONE status",
+	Short: "Move tasks with DONE status to the archive",
 	Args:  cobra.NoArgs,
 	Run: func(cmd *cobra.Command, args []string) {
-		if !ConfirmAction("Are you sure you want to delete all DONE tasks? This action is irreversible.") {
+		if !ConfirmAction("Are you sure you want to archive all DONE tasks") {
 			fmt.Println("Operation cancelled")
 			return
 		}
@@ -230,18 +409,124 @@ var cleanCmd = &cobra.Command{
 			return
 		}
 
-		fmt.Printf("Successfully deleted %d DONE tasks.\n", count)
+		fmt.Printf("Successfully archived %d DONE tasks (see 'archive list').\n", count)
+	},
+}
+
+var historyCmd = &cobra.Command{
+	Use:   "history [task_id]",
+	Short: "show the change history of a task, including trashed and archived ones",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		taskID, err := strconv.Atoi(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Task ID must be an integer. %v\n", err)
+			return
+		}
+		task, ok, err := tm.FindTask(taskID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading task: %v\n", err)
+			return
+		}
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: Task with ID %d not found.\n", taskID)
+			return
+		}
+		if len(task.TaskHistory) == 0 {
+			fmt.Printf("No history recorded for task ID %d.\n", taskID)
+			return
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.Header("When", "Who", "Operation", "Field", "Old", "New")
+		for _, entry := range task.TaskHistory {
+			tableRow := []string{entry.At, entry.Actor, entry.Operation, entry.Field, entry.OldValue, entry.NewValue}
+			err := table.Append(tableRow)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error appending row: %v\n", err)
+			}
+		}
+		err = table.Render()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error rendering table: %v\n", err)
+		}
+		fmt.Printf("History of task ID %d '%s' (Total: %d):\n", taskID, task.TaskName, len(task.TaskHistory))
+	},
+}
+
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Undo the last change",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		op, ok, err := tm.Undo()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error undoing: %v\n", err)
+			return
+		}
+		if !ok {
+			fmt.Println("Nothing to undo.")
+			return
+		}
+		fmt.Printf("↩️ Undone '%s' (%d tasks).\n", op.Kind, len(op.Changes))
+	},
+}
+
+var redoCmd = &cobra.Command{
+	Use:   "redo",
+	Short: "Redo the last undone change",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		op, ok, err := tm.Redo()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error redoing: %v\n", err)
+			return
+		}
+		if !ok {
+			fmt.Println("Nothing to redo.")
+			return
+		}
+		fmt.Printf("↪️ Redone '%s' (%d tasks).\n", op.Kind, len(op.Changes))
 	},
 }
 
+// exitCode - код завершения для команд, которые сами сообщают об ошибке, но должны завершиться неуспешно
+var exitCode int
+
 func execute() {
 	if err := mainCmd.Execute(); err != nil {
 		fmt.Println(err)
 		os.Exit(1)
 	}
+	os.Exit(exitCode)
 }
 
 func init() {
+	mainCmd.PersistentFlags().StringVar(&storageKind, "storage", "json", "storage backend: json, journal or sqlite")
+	mainCmd.PersistentFlags().StringVar(&storagePath, "file", "", "path to the tasks file (default tasks.json or tasks.db)")
+	mainCmd.PersistentFlags().StringVar(&workflowPath, "workflow", "workflow.json", "path to the workflow file with custom statuses and transitions")
+	mainCmd.PersistentFlags().DurationVar(&lockTimeout, "lock-timeout", task_manager.DefaultLockTimeout, "how long to wait for another TaskTracker process to release the tasks file")
+
+	addCmd.Flags().StringVar(&addPriority, "priority", "", "task priority: low, medium, high or critical (default medium)")
+	updateCmd.Flags().StringVar(&updatePriority, "priority", "", "new task priority: low, medium, high or critical")
+	addCmd.Flags().StringVar(&addDue, "due", "", "due date: 2026-11-01, today, tomorrow, friday or +3d, +2w, +1m")
+	updateCmd.Flags().StringVar(&updateDue, "due", "", "new due date (same formats as add --due), none removes it")
+	listTasksCmd.Flags().StringVar(&listPriority, "priority", "", "only show tasks with this priority")
+	addCmd.Flags().StringSliceVar(&addTags, "tag", nil, "tag the task (repeat or separate with commas)")
+	listTasksCmd.Flags().StringVar(&listTags, "tag", "", "only show tasks with all of these comma-separated tags")
+	listTasksCmd.Flags().BoolVar(&listAnyTag, "any", false, "with --tag, show tasks that have at least one of the tags")
+	addCmd.Flags().StringVar(&addProject, "project", "", "add the task to this project")
+	updateCmd.Flags().StringVar(&updateProject, "project", "", "move the task to this project (none removes it from its project)")
+	addCmd.Flags().StringVar(&addRecur, "recur", "", "repeat the task: daily, weekly, weekly:mon,thu, monthly, monthly:15, every:3d or cron:\"0 9 * * 1-5\"")
+	updateCmd.Flags().StringVar(&updateRecur, "recur", "", "new recurrence rule (same formats as add --recur), none stops the series")
+	addCmd.Flags().StringVar(&addEstimate, "estimate", "", "effort estimate: story points (3, 3pt) or a duration (2h, 1h30m, 45m)")
+	updateCmd.Flags().StringVar(&updateEstimate, "estimate", "", "new effort estimate (same formats as add --estimate), none removes it")
+	listTasksCmd.Flags().BoolVar(&listRecurring, "recurring", false, "only show recurring tasks")
+	addCmd.Flags().StringVar(&addParent, "parent", "", "make the task a subtask of this task ID (none adds a top-level task)")
+	updateCmd.Flags().StringVar(&updateParent, "parent", "", "make the task a subtask of this task ID (none makes it a top-level task)")
+	markTaskCmd.Flags().BoolVar(&markCascade, "cascade", false, "with DONE, also mark all open subtasks as DONE")
+	listTasksCmd.Flags().BoolVar(&listReady, "ready", false, "only show TODO tasks that are not blocked by unfinished tasks")
+	listTasksCmd.Flags().StringVar(&listProject, "project", "", "only show tasks of this project (none for tasks without a project)")
+
 	mainCmd.AddCommand(addCmd)
 	mainCmd.AddCommand(updateCmd)
 	mainCmd.AddCommand(deleteCmd)
@@ -249,16 +534,12 @@ func init() {
 	mainCmd.AddCommand(listTasksCmd)
 	mainCmd.AddCommand(searchCmd)
 	mainCmd.AddCommand(cleanCmd)
+	mainCmd.AddCommand(historyCmd)
+	mainCmd.AddCommand(undoCmd)
+	mainCmd.AddCommand(redoCmd)
 }
 
 func main() {
-	var err error
-
-	tm, err = task_manager.NewTaskManager("tasks.json")
-	if err != nil {
-		fmt.Println("Error creating task manager:", err)
-		os.Exit(1)
-	}
 	execute()
 }
 
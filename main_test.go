@@ -0,0 +1,85 @@
+package main
+
+import (
+	"os"
+	"testing"
+
+	"github.com/TaskTrackerCLI/structures"
+	"github.com/TaskTrackerCLI/task_manager"
+	"github.com/spf13/cobra"
+)
+
+// runQuiet - выполняет команду с флагами, вывод команды отбрасывается
+func runQuiet(t *testing.T, cmd *cobra.Command, flags map[string]string, args ...string) {
+	t.Helper()
+	for name, value := range flags {
+		if err := cmd.Flags().Set(name, value); err != nil {
+			t.Fatalf("Failed to set --%s: %v", name, err)
+		}
+	}
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", os.DevNull, err)
+	}
+	defer devNull.Close()
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = devNull, devNull
+	cmd.Run(cmd, args)
+	os.Stdout, os.Stderr = stdout, stderr
+}
+
+// TestParentFlag - add --parent и update --parent принимают одни и те же значения и проверяют их одинаково.
+func TestParentFlag(t *testing.T) {
+	var err error
+	tm, err = task_manager.NewTaskManagerWithStore(task_manager.NewMemoryStore())
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+	parent, _ := tm.AddTask("release", "")
+	child, _ := tm.AddTask("changelog", "")
+	tasks := func() []structures.Task {
+		tasks, err := tm.ListAllTasks()
+		if err != nil {
+			t.Fatalf("ListAllTasks() error = %v", err)
+		}
+		return tasks
+	}
+	// added - id последнего добавленного таска
+	added := func() int {
+		all := tasks()
+		return all[len(all)-1].TaskId
+	}
+	parentOf := func(id int) int {
+		task, _, err := tm.GetTask(id)
+		if err != nil {
+			t.Fatalf("GetTask() error = %v", err)
+		}
+		return task.TaskParentId
+	}
+
+	for _, value := range []string{"abc", "42"} {
+		runQuiet(t, addCmd, map[string]string{"parent": value}, "docs", "")
+		if got := len(tasks()); got != 2 {
+			t.Errorf("add --parent %s added a task, got %d tasks", value, got)
+		}
+		runQuiet(t, updateCmd, map[string]string{"parent": value}, "2")
+		if got := parentOf(child); got != 0 {
+			t.Errorf("update --parent %s set parent %d", value, got)
+		}
+	}
+
+	runQuiet(t, addCmd, map[string]string{"parent": "1"}, "docs", "")
+	if got := parentOf(added()); got != parent {
+		t.Errorf("add --parent 1 set parent %d, want %d", got, parent)
+	}
+	runQuiet(t, updateCmd, map[string]string{"parent": "1"}, "2")
+	if got := parentOf(child); got != parent {
+		t.Errorf("update --parent 1 set parent %d, want %d", got, parent)
+	}
+	runQuiet(t, addCmd, map[string]string{"parent": "none"}, "notes", "")
+	notes := added()
+	runQuiet(t, updateCmd, map[string]string{"parent": "none"}, "2")
+	if parentOf(notes) != 0 || parentOf(child) != 0 {
+		t.Errorf("--parent none left parents %d and %d, want top-level tasks", parentOf(notes), parentOf(child))
+	}
+}
@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/TaskTrackerCLI/task_manager"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	projectMoveTo      string
+	projectDeleteTasks bool
+)
+
+var projectCmd = &cobra.Command{
+	Use:   "project",
+	Short: "manage projects",
+}
+
+var projectCreateCmd = &cobra.Command{
+	Use:   "create [name]",
+	Short: "create a project",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		project, err := tm.CreateProject(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error creating project: %v\n", err)
+			return
+		}
+		fmt.Printf("📁 Project '%s' created. ID: %d\n", project.ProjectName, project.ProjectId)
+	},
+}
+
+var projectListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list projects with their progress",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		projects, err := tm.ListProjects()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing projects: %v\n", err)
+			return
+		}
+		if len(projects) == 0 {
+			fmt.Println("No projects yet. Create one with 'project create [name]'.")
+			return
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.Header("ID", "Name", "Todo", "In progress", "Done", "Progress")
+		for _, progress := range projects {
+			tableRow := []string{strconv.Itoa(progress.Project.ProjectId), progress.Project.ProjectName,
+				strconv.Itoa(progress.Todo), strconv.Itoa(progress.InProgress), strconv.Itoa(progress.Done),
+				fmt.Sprintf("%d%%", progress.Percent())}
+			err := table.Append(tableRow)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error appending row: %v\n", err)
+			}
+		}
+		err = table.Render()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error rendering table: %v\n", err)
+		}
+	},
+}
+
+var projectRenameCmd = &cobra.Command{
+	Use:   "rename [name] [new_name]",
+	Short: "rename a project",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		ok, err := tm.RenameProject(args[0], args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error renaming project: %v\n", err)
+			return
+		}
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: Project '%s' not found.\n", args[0])
+			return
+		}
+		fmt.Printf("📁 Project '%s' renamed to '%s'.\n", args[0], args[1])
+	},
+}
+
+var projectDeleteCmd = &cobra.Command{
+	Use:   "delete [name]",
+	Short: "delete a project, moving its tasks with --move-to or trashing them with --delete-tasks",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ok, err := tm.DeleteProject(args[0], projectMoveTo, projectDeleteTasks)
+		if errors.Is(err, task_manager.ErrProjectNotEmpty) {
+			fmt.Fprintf(os.Stderr, "Error: Project '%s' still has tasks. Use --move-to [project|none] or --delete-tasks.\n", args[0])
+			return
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error deleting project: %v\n", err)
+			return
+		}
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: Project '%s' not found.\n", args[0])
+			return
+		}
+		fmt.Printf("🗑️ Project '%s' deleted.\n", args[0])
+	},
+}
+
+func init() {
+	projectDeleteCmd.Flags().StringVar(&projectMoveTo, "move-to", "", "move the project tasks to this project (none detaches them)")
+	projectDeleteCmd.Flags().BoolVar(&projectDeleteTasks, "delete-tasks", false, "move the project tasks to the trash")
+
+	projectCmd.AddCommand(projectCreateCmd)
+	projectCmd.AddCommand(projectListCmd)
+	projectCmd.AddCommand(projectRenameCmd)
+	projectCmd.AddCommand(projectDeleteCmd)
+	mainCmd.AddCommand(projectCmd)
+}
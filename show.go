@@ -0,0 +1,188 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TaskTrackerCLI/dates"
+	"github.com/TaskTrackerCLI/structures"
+	"github.com/TaskTrackerCLI/task_manager"
+	"github.com/spf13/cobra"
+)
+
+// showWidth - ширина, по которой переносятся описание и заметки в show
+const showWidth = 80
+
+var showCmd = &cobra.Command{
+	Use:   "show [task_id]",
+	Short: "show every detail of a task, its description and comments, even if it is archived or trashed",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		// show используют в скриптах, поэтому о ненайденном таске сообщает и код завершения
+		exitCode = 1
+		taskID, err := strconv.Atoi(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Task ID must be an integer. %v\n", err)
+			return
+		}
+		task, ok, err := tm.FindTask(taskID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading task: %v\n", err)
+			return
+		}
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: Task with ID %d not found.\n", taskID)
+			return
+		}
+		blockers, err := tm.Blockers()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error checking dependencies: %v\n", err)
+			return
+		}
+		project, err := projectName(task.TaskProjectId)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading projects: %v\n", err)
+			return
+		}
+		parent := ""
+		if task.TaskParentId != 0 {
+			parent = "#" + strconv.Itoa(task.TaskParentId)
+			if parentTask, ok, err := tm.FindTask(task.TaskParentId); err == nil && ok {
+				parent += " " + parentTask.TaskName
+			}
+		}
+		exitCode = 0
+		printTask(task, blockers[task.TaskId], project, parent, time.Now())
+	},
+}
+
+// projectName - имя проекта по id, пусто для тасков без проекта
+func projectName(projectId int) (string, error) {
+	if projectId == 0 {
+		return "", nil
+	}
+	projects, err := tm.ListProjects()
+	if err != nil {
+		return "", err
+	}
+	for _, progress := range projects {
+		if progress.Project.ProjectId == projectId {
+			return progress.Project.ProjectName, nil
+		}
+	}
+	return "#" + strconv.Itoa(projectId), nil
+}
+
+// printTask - таск целиком: все поля по одному в строке, описание и заметки с переносом строк
+func printTask(task structures.Task, blockers []int, project, parent string, now time.Time) {
+	fmt.Printf("#%d %s\n\n", task.TaskId, task.TaskName)
+	blockedBy := make([]string, len(task.TaskBlockedBy))
+	for i, id := range task.TaskBlockedBy {
+		blockedBy[i] = strconv.Itoa(id)
+	}
+	timeSpent := ""
+	if len(task.TaskWorklog) > 0 {
+		timeSpent = fmt.Sprintf("%s (%s)", formatDuration(task_manager.TimeSpent(task)), plural(len(task.TaskWorklog), "entry", "entries"))
+	}
+	history := ""
+	if len(task.TaskHistory) > 0 {
+		history = fmt.Sprintf("%s, see 'history %d'", plural(len(task.TaskHistory), "change", "changes"), task.TaskId)
+	}
+	fields := [][2]string{
+		{"Status", formatStatus(task.TaskStatus, blockers)},
+		{"Priority", task.TaskPriority},
+		{"Due", formatDue(task)},
+		{"Estimate", task.TaskEstimate},
+		{"Checklist", formatChecklist(task)},
+		{"Time spent", timeSpent},
+		{"Project", project},
+		{"Parent", parent},
+		{"Depends on", strings.Join(blockedBy, ", ")},
+		{"Tags", strings.Join(task.TaskTags, ", ")},
+		{"Created", formatTimestamp(task.TaskCreatedAt, now)},
+		{"Updated", formatTimestamp(task.TaskUpdatedAt, now)},
+		{"History", history},
+	}
+	// show находит и таски из корзины и архива, у них видно, когда их туда убрали
+	if task.TaskArchivedAt != "" {
+		fields = append(fields, [2]string{"Archived", formatTimestamp(task.TaskArchivedAt, now)})
+	}
+	if task.TaskDeletedAt != "" {
+		fields = append(fields, [2]string{"Deleted", formatTimestamp(task.TaskDeletedAt, now)})
+	}
+	for _, field := range fields {
+		value := field[1]
+		if value == "" {
+			value = "-"
+		}
+		fmt.Printf("%-11s %s\n", field[0]+":", value)
+	}
+
+	fmt.Println("\nDescription:")
+	if strings.TrimSpace(task.TaskDescription) == "" {
+		fmt.Println("  -")
+	} else {
+		printWrapped(task.TaskDescription, "  ")
+	}
+
+	if len(task.TaskChecklist) > 0 {
+		fmt.Println("\n☑️ Checklist:")
+		printChecklist(task.TaskChecklist)
+	}
+
+	if len(task.TaskComments) == 0 {
+		return
+	}
+	fmt.Printf("\n💬 Comments (%d):\n", len(task.TaskComments))
+	for _, comment := range task.TaskComments {
+		fmt.Printf("\n  %s, %s:\n", comment.Actor, formatTimestamp(comment.At, now))
+		printWrapped(comment.Text, "    ")
+	}
+}
+
+// formatTimestamp - время из таска в местном часовом поясе и относительно now: 2026-10-13 09:30 (3 days ago)
+func formatTimestamp(value string, now time.Time) string {
+	if value == "" {
+		return ""
+	}
+	at, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return value
+	}
+	return fmt.Sprintf("%s (%s)", at.In(now.Location()).Format("2006-01-02 15:04"), dates.Ago(at, now))
+}
+
+// plural - количество со словом в нужном числе: 1 entry, 3 entries
+func plural(count int, one, many string) string {
+	if count == 1 {
+		return "1 " + one
+	}
+	return strconv.Itoa(count) + " " + many
+}
+
+// printWrapped - печатает текст с отступом indent, перенося строки по словам в пределах showWidth.
+// Переводы строк в тексте сохраняются, слово длиннее строки не разрывается
+func printWrapped(text, indent string) {
+	width := showWidth - len(indent)
+	for _, paragraph := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		line := ""
+		for _, word := range strings.Fields(paragraph) {
+			if line != "" && len([]rune(line))+1+len([]rune(word)) > width {
+				fmt.Println(indent + line)
+				line = ""
+			}
+			if line != "" {
+				line += " "
+			}
+			line += word
+		}
+		fmt.Println(strings.TrimRight(indent+line, " "))
+	}
+}
+
+func init() {
+	mainCmd.AddCommand(showCmd)
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/TaskTrackerCLI/task_manager"
+)
+
+// runShow - выполняет show для id и возвращает его вывод и код завершения
+func runShow(t *testing.T, id int) (string, int) {
+	t.Helper()
+	reader, writer, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("Failed to create pipe: %v", err)
+	}
+	stdout := os.Stdout
+	os.Stdout = writer
+	exitCode = 0
+	showCmd.Run(showCmd, []string{strconv.Itoa(id)})
+	os.Stdout = stdout
+	if err := writer.Close(); err != nil {
+		t.Fatalf("Failed to close pipe: %v", err)
+	}
+	output, err := io.ReadAll(reader)
+	if err != nil {
+		t.Fatalf("Failed to read output: %v", err)
+	}
+	return string(output), exitCode
+}
+
+// TestShowFindsTrashedAndArchivedTasks - show выводит таски из корзины и архива вместе с тем, когда их туда убрали,
+// и завершается с ошибкой только для несуществующего таска.
+func TestShowFindsTrashedAndArchivedTasks(t *testing.T) {
+	var err error
+	tm, err = task_manager.NewTaskManagerWithStore(task_manager.NewMemoryStore())
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+	trashed, _ := tm.AddTask("old idea", "")
+	archived, _ := tm.AddTask("shipped", "")
+	if _, err := tm.DeleteTask(trashed); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	if _, err := tm.MarkTaskAsDone(archived); err != nil {
+		t.Fatalf("MarkTaskAsDone() error = %v", err)
+	}
+	if _, err := tm.CleanDoneTasks(); err != nil {
+		t.Fatalf("CleanDoneTasks() error = %v", err)
+	}
+
+	for id, field := range map[int]string{trashed: "Deleted:", archived: "Archived:"} {
+		output, code := runShow(t, id)
+		if code != 0 {
+			t.Errorf("show %d exit code = %d, want 0", id, code)
+		}
+		if !strings.Contains(output, field) {
+			t.Errorf("show %d output has no %q line:\n%s", id, field, output)
+		}
+	}
+	if _, code := runShow(t, 42); code != 1 {
+		t.Errorf("show of a missing task exit code = %d, want 1", code)
+	}
+}
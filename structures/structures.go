@@ -5,6 +5,94 @@ type Task struct {
 	TaskName        string `json:"task_name"`
 	TaskDescription string `json:"task_description"`
 	TaskStatus      string `json:"task_status"`
-	TaskCreatedAt   string `json:"task_created_at"`
-	TaskUpdatedAt   string `json:"task_updated_at"`
+	TaskPriority    string `json:"task_priority"`
+	// TaskDueDate - срок в формате 2006-01-02, пусто если срока нет
+	TaskDueDate string `json:"task_due_date,omitempty"`
+	// TaskRecurrence - правило повторения (daily, weekly:mon,thu, ...), пусто у разовых тасков
+	TaskRecurrence string `json:"task_recurrence,omitempty"`
+	// TaskEstimate - оценка трудоемкости: story points (3pt) или время (1h30m), пусто если не оценен
+	TaskEstimate string `json:"task_estimate,omitempty"`
+	// TaskParentId - id родительского таска для подзадач, 0 - таск верхнего уровня
+	TaskParentId int `json:"task_parent_id,omitempty"`
+	// TaskProjectId - id проекта, к которому относится таск, 0 - без проекта
+	TaskProjectId int `json:"task_project_id,omitempty"`
+	// TaskTags - метки таска в нижнем регистре, отсортированы и не повторяются
+	TaskTags []string `json:"task_tags,omitempty"`
+	// TaskBlockedBy - id тасков, которые нужно завершить до начала этого, по возрастанию
+	TaskBlockedBy []int  `json:"task_blocked_by,omitempty"`
+	TaskCreatedAt string `json:"task_created_at"`
+	TaskUpdatedAt string `json:"task_updated_at"`
+	// TaskDeletedAt - когда таск перенесен в корзину, пусто у обычных тасков
+	TaskDeletedAt string `json:"task_deleted_at,omitempty"`
+	// TaskArchivedAt - когда выполненный таск убран в архив, пусто у обычных тасков
+	TaskArchivedAt string `json:"task_archived_at,omitempty"`
+	// TaskWorklog - отрезки времени, потраченного на таск, в порядке добавления
+	TaskWorklog []WorklogEntry `json:"task_worklog,omitempty"`
+	// TaskChecklist - пункты чек-листа таска в заданном порядке
+	TaskChecklist []ChecklistItem `json:"task_checklist,omitempty"`
+	// TaskComments - заметки к таску в порядке добавления, только дописываются
+	TaskComments []Comment `json:"task_comments,omitempty"`
+	// TaskHistory - журнал изменений полей таска, только дописывается
+	TaskHistory []HistoryEntry `json:"task_history,omitempty"`
+}
+
+// Project - проект, объединяющий таски
+type Project struct {
+	ProjectId        int    `json:"project_id"`
+	ProjectName      string `json:"project_name"`
+	ProjectCreatedAt string `json:"project_created_at"`
+}
+
+// WorklogEntry - отрезок времени работы над таском: по таймеру или внесенный вручную
+type WorklogEntry struct {
+	Start  string `json:"start"`
+	End    string `json:"end"`
+	Actor  string `json:"actor"`
+	Manual bool   `json:"manual,omitempty"`
+}
+
+// ChecklistItem - пункт чек-листа таска
+type ChecklistItem struct {
+	Text string `json:"text"`
+	Done bool   `json:"done,omitempty"`
+}
+
+// Comment - заметка к таску: кто и когда ее оставил
+type Comment struct {
+	At    string `json:"at"`
+	Actor string `json:"actor"`
+	Text  string `json:"text"`
+}
+
+// HistoryEntry - одно изменение поля таска: кто, когда и в рамках какой операции его сделал
+type HistoryEntry struct {
+	At        string `json:"at"`
+	Actor     string `json:"actor"`
+	Operation string `json:"operation"`
+	Field     string `json:"field"`
+	OldValue  string `json:"old_value"`
+	NewValue  string `json:"new_value"`
+}
+
+// Clone - копия таска, не разделяющая срезы с исходным
+func (task Task) Clone() Task {
+	if task.TaskHistory != nil {
+		task.TaskHistory = append([]HistoryEntry(nil), task.TaskHistory...)
+	}
+	if task.TaskChecklist != nil {
+		task.TaskChecklist = append([]ChecklistItem(nil), task.TaskChecklist...)
+	}
+	if task.TaskComments != nil {
+		task.TaskComments = append([]Comment(nil), task.TaskComments...)
+	}
+	if task.TaskTags != nil {
+		task.TaskTags = append([]string(nil), task.TaskTags...)
+	}
+	if task.TaskWorklog != nil {
+		task.TaskWorklog = append([]WorklogEntry(nil), task.TaskWorklog...)
+	}
+	if task.TaskBlockedBy != nil {
+		task.TaskBlockedBy = append([]int(nil), task.TaskBlockedBy...)
+	}
+	return task
 }
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "add or remove task tags",
+}
+
+var tagAddCmd = &cobra.Command{
+	Use:   "add [task_id] [tag]",
+	Short: "add a tag to a task",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		changeTag(args, "added to", tm.AddTag)
+	},
+}
+
+var tagRemoveCmd = &cobra.Command{
+	Use:   "remove [task_id] [tag]",
+	Short: "remove a tag from a task",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		changeTag(args, "removed from", tm.RemoveTag)
+	},
+}
+
+func changeTag(args []string, action string, change func(id int, tag string) (bool, error)) {
+	taskID, err := strconv.Atoi(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: Task ID must be an integer. %v\n", err)
+		return
+	}
+	ok, err := change(taskID, args[1])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error changing tags: %v\n", err)
+		return
+	}
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Error: Task with ID %d not found.\n", taskID)
+		return
+	}
+	fmt.Printf("🏷️ Tag '%s' %s task ID %d.\n", args[1], action, taskID)
+}
+
+var tagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "list all tags with the number of tasks",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		counts, err := tm.TagCounts()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing tags: %v\n", err)
+			return
+		}
+		if len(counts) == 0 {
+			fmt.Println("No tags yet.")
+			return
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.Header("Tag", "Tasks")
+		for _, count := range counts {
+			err := table.Append([]string{count.Tag, strconv.Itoa(count.Count)})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error appending row: %v\n", err)
+			}
+		}
+		err = table.Render()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error rendering table: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	tagCmd.AddCommand(tagAddCmd)
+	tagCmd.AddCommand(tagRemoveCmd)
+	mainCmd.AddCommand(tagCmd)
+	mainCmd.AddCommand(tagsCmd)
+}
@@ -0,0 +1,124 @@
+package task_manager
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TaskTrackerCLI/structures"
+)
+
+// isArchived - таск убран в архив
+func isArchived(task structures.Task) bool {
+	return task.TaskArchivedAt != ""
+}
+
+// archivedSince - таск, убранный в архив в archivedAt, убран не раньше since. Непонятное время считается подходящим
+func archivedSince(archivedAt string, since time.Time) bool {
+	at, err := time.Parse(time.RFC3339, archivedAt)
+	return err != nil || !at.Before(since)
+}
+
+// listUnarchived - таски рабочего набора и корзины. Хранилище с ArchiveStore отдает их, не читая архив
+func listUnarchived(store Store) ([]structures.Task, error) {
+	if archiveStore, ok := store.(ArchiveStore); ok {
+		return archiveStore.ListUnarchived()
+	}
+	tasks, err := store.List()
+	if err != nil {
+		return nil, err
+	}
+	result := tasks[:0]
+	for _, task := range tasks {
+		if !isArchived(task) {
+			result = append(result, task)
+		}
+	}
+	return result, nil
+}
+
+// listArchivedSince - архивные таски, убранные в архив не раньше since
+func listArchivedSince(store Store, since time.Time) ([]structures.Task, error) {
+	if archiveStore, ok := store.(ArchiveStore); ok {
+		return archiveStore.ListArchivedSince(since)
+	}
+	tasks, err := store.List()
+	if err != nil {
+		return nil, err
+	}
+	result := tasks[:0]
+	for _, task := range tasks {
+		if isArchived(task) && archivedSince(task.TaskArchivedAt, since) {
+			result = append(result, task)
+		}
+	}
+	return result, nil
+}
+
+func moveToArchive(store Store, task structures.Task) error {
+	task.TaskArchivedAt = time.Now().Format(time.RFC3339)
+	return store.Put(task)
+}
+
+// ListArchive - таски в архиве, начиная с заархивированных последними
+func (taskManager *TaskManager) ListArchive() ([]structures.Task, error) {
+	return taskManager.SearchArchive("")
+}
+
+// SearchArchive - ищет подстроку в имени и описании тасков из архива, пустой запрос возвращает весь архив
+func (taskManager *TaskManager) SearchArchive(query string) ([]structures.Task, error) {
+	taskManager.mu.RLock()
+	defer taskManager.mu.RUnlock()
+
+	tasks, err := taskManager.store.List()
+	if err != nil {
+		return nil, err
+	}
+	query = strings.ToLower(query)
+	archive := make([]structures.Task, 0)
+	for _, task := range tasks {
+		if !isArchived(task) || isTrashed(task) {
+			continue
+		}
+		if strings.Contains(strings.ToLower(task.TaskName), query) || strings.Contains(strings.ToLower(task.TaskDescription), query) {
+			archive = append(archive, task)
+		}
+	}
+	sort.Slice(archive, func(i, j int) bool {
+		if archive[i].TaskArchivedAt != archive[j].TaskArchivedAt {
+			return archive[i].TaskArchivedAt > archive[j].TaskArchivedAt
+		}
+		return archive[i].TaskId < archive[j].TaskId
+	})
+	return archive, nil
+}
+
+// UnarchiveTask - возвращает таск из архива в рабочий набор. false, если в архиве нет таска с таким id
+func (taskManager *TaskManager) UnarchiveTask(id int) (bool, error) {
+	var found bool
+	err := taskManager.mutate(OperationUnarchive, func(store Store) error {
+		task, ok, err := store.Get(id)
+		if err != nil || !ok || !isArchived(task) || isTrashed(task) {
+			return err
+		}
+		found = true
+		// проект мог быть удален, пока таск лежал в архиве
+		if task.TaskProjectId != 0 {
+			registry, err := loadProjects(store)
+			if err != nil {
+				return err
+			}
+			if !registry.has(task.TaskProjectId) {
+				task.TaskProjectId = 0
+			}
+		}
+		task.TaskArchivedAt = ""
+		task.TaskUpdatedAt = time.Now().Format(time.RFC3339)
+		return store.Put(task)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to unarchive task: %w", err)
+	}
+	return found, nil
+}
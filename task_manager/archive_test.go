@@ -0,0 +1,200 @@
+package task_manager
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TaskTrackerCLI/structures"
+)
+
+// TestArchive - проверяет, что clean убирает выполненные таски в архив, а не удаляет их, и что их можно найти и вернуть.
+func TestArchive(t *testing.T) {
+	stores := map[string]func(t *testing.T) Store{
+		"MemoryStore": func(t *testing.T) Store {
+			return NewMemoryStore()
+		},
+		"JSONFileStore": func(t *testing.T) Store {
+			return NewJSONFileStore(filepath.Join(t.TempDir(), "tasks.json"))
+		},
+		"JournalStore": func(t *testing.T) Store {
+			return NewJournalStore(filepath.Join(t.TempDir(), "tasks.json"))
+		},
+		"SQLiteStore": func(t *testing.T) Store {
+			return NewSQLiteStore(filepath.Join(t.TempDir(), "tasks.db"))
+		},
+	}
+
+	ids := func(tasks []structures.Task, err error) []int {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("query error = %v", err)
+		}
+		result := make([]int, 0, len(tasks))
+		for _, task := range tasks {
+			result = append(result, task.TaskId)
+		}
+		return result
+	}
+	expect := func(name string, got []int, want ...int) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Fatalf("%s = %v, want %v", name, got, want)
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Fatalf("%s = %v, want %v", name, got, want)
+			}
+		}
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			tm, err := NewTaskManagerWithStore(newStore(t))
+			if err != nil {
+				t.Fatalf("Failed to create TaskManager: %v", err)
+			}
+			t.Cleanup(func() { _ = tm.Close() })
+
+			for _, name := range []string{"Q3 retro notes", "Fix bug", "Q3 roadmap"} {
+				if _, err := tm.AddTask(name, ""); err != nil {
+					t.Fatalf("AddTask() error = %v", err)
+				}
+			}
+			for _, id := range []int{1, 3} {
+				if _, err := tm.MarkTaskAsDone(id); err != nil {
+					t.Fatalf("MarkTaskAsDone() error = %v", err)
+				}
+			}
+			if count, err := tm.CleanDoneTasks(); count != 2 || err != nil {
+				t.Fatalf("CleanDoneTasks() = %d, %v, want 2", count, err)
+			}
+
+			expect("ListAllTasks", ids(tm.ListAllTasks()), 2)
+			expect("ListDoneTasks", ids(tm.ListDoneTasks()), []int{}...)
+			expect("SearchTasks", ids(tm.SearchTasks("q3")), []int{}...)
+			expect("ListTrash", ids(tm.ListTrash()), []int{}...)
+			expect("ListArchive", ids(tm.ListArchive()), 1, 3)
+			expect("SearchArchive", ids(tm.SearchArchive("ROADMAP")), 3)
+			if ok, err := tm.DeleteTask(1); ok || err != nil {
+				t.Errorf("DeleteTask() of an archived task = %v, %v, want false", ok, err)
+			}
+
+			if ok, err := tm.UnarchiveTask(3); !ok || err != nil {
+				t.Fatalf("UnarchiveTask(3) = %v, %v", ok, err)
+			}
+			if ok, err := tm.UnarchiveTask(2); ok || err != nil {
+				t.Errorf("UnarchiveTask() of an active task = %v, %v, want false", ok, err)
+			}
+			expect("ListDoneTasks after unarchive", ids(tm.ListDoneTasks()), 3)
+			expect("ListArchive after unarchive", ids(tm.ListArchive()), 1)
+		})
+	}
+}
+
+// TestArchiveFile - JSONFileStore держит архив в отдельном файле и не читает его для рабочего набора,
+// а архивные таски из файлов версии 3 переезжают туда при первом сохранении.
+func TestArchiveFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	legacy := `{"schema_version": 3, "created_at": "2025-03-01T00:00:00Z", "next_id": 4, "tasks": {
+		"1": {"task_id": 1, "task_name": "old retro", "task_status": "DONE", "task_priority": "LOW", "task_archived_at": "2025-04-01T00:00:00Z"},
+		"2": {"task_id": 2, "task_name": "open", "task_status": "TODO", "task_priority": "LOW"},
+		"3": {"task_id": 3, "task_name": "finished", "task_status": "DONE", "task_priority": "LOW"}}, "meta": {}}`
+	if err := os.WriteFile(path, []byte(legacy), 0644); err != nil {
+		t.Fatalf("Failed to write legacy file: %v", err)
+	}
+	fileTasks := func(path string) map[string]json.RawMessage {
+		t.Helper()
+		content, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", path, err)
+		}
+		var file struct {
+			Tasks map[string]json.RawMessage `json:"tasks"`
+		}
+		if err := json.Unmarshal(content, &file); err != nil {
+			t.Fatalf("Invalid %s: %v", path, err)
+		}
+		return file.Tasks
+	}
+
+	tm, err := NewTaskManager(path)
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+	if ok, err := tm.LogWork(3, time.Hour, time.Now()); !ok || err != nil {
+		t.Fatalf("LogWork(3) = %v, %v", ok, err)
+	}
+	if count, err := tm.CleanDoneTasks(); count != 1 || err != nil {
+		t.Fatalf("CleanDoneTasks() = %d, %v, want 1", count, err)
+	}
+	if tasks := fileTasks(path); len(tasks) != 1 || tasks["2"] == nil {
+		t.Errorf("tasks file keeps %d tasks, want only the open task 2", len(tasks))
+	}
+	if tasks := fileTasks(path + ".archive"); len(tasks) != 2 || tasks["1"] == nil || tasks["3"] == nil {
+		t.Errorf("archive file has %d tasks, want tasks 1 and 3", len(tasks))
+	}
+
+	store := NewJSONFileStore(path)
+	tm, err = NewTaskManagerWithStore(store)
+	if err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	if tasks, err := tm.ListAllTasks(); err != nil || len(tasks) != 1 {
+		t.Errorf("ListAllTasks() = %d tasks, %v, want 1", len(tasks), err)
+	}
+	if _, err := tm.Blockers(); err != nil {
+		t.Errorf("Blockers() error = %v", err)
+	}
+	id, err := tm.AddTask("blocker", "")
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	if ok, err := tm.AddDependency(2, id); !ok || err != nil {
+		t.Errorf("AddDependency() = %v, %v", ok, err)
+	}
+	if ok, err := tm.DeleteTask(id); !ok || err != nil {
+		t.Errorf("DeleteTask() = %v, %v", ok, err)
+	}
+	if trash, err := tm.ListTrash(); err != nil || len(trash) != 1 {
+		t.Errorf("ListTrash() = %d tasks, %v, want 1", len(trash), err)
+	}
+	if ok, err := tm.RestoreTask(id); !ok || err != nil {
+		t.Errorf("RestoreTask() = %v, %v", ok, err)
+	}
+	if _, err := tm.EmptyTrash(0); err != nil {
+		t.Errorf("EmptyTrash() error = %v", err)
+	}
+	if _, err := tm.ListProjects(); err != nil {
+		t.Errorf("ListProjects() error = %v", err)
+	}
+	tomorrow := time.Now().AddDate(0, 0, 1)
+	if sheet, err := tm.Timesheet(tomorrow, tomorrow); err != nil || sheet.Total != 0 {
+		t.Errorf("Timesheet() for tomorrow = %v, %v, want nothing", sheet.Total, err)
+	}
+	if store.archive != nil {
+		t.Errorf("working set commands read the archive file")
+	}
+	if sheet, err := tm.Timesheet(time.Now(), time.Now()); err != nil || sheet.Total != time.Hour {
+		t.Errorf("Timesheet() for today = %v, %v, want the hour logged on archived task 3", sheet.Total, err)
+	}
+	if archive, err := tm.ListArchive(); err != nil || len(archive) != 2 {
+		t.Errorf("ListArchive() = %d tasks, %v, want 2", len(archive), err)
+	}
+
+	if ok, err := tm.UnarchiveTask(1); !ok || err != nil {
+		t.Fatalf("UnarchiveTask(1) = %v, %v", ok, err)
+	}
+	tm, err = NewTaskManager(path)
+	if err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	if archive, err := tm.ListArchive(); err != nil || len(archive) != 1 || archive[0].TaskId != 3 {
+		t.Errorf("ListArchive() after unarchive = %v, %v, want task 3", archive, err)
+	}
+	if task, ok, err := tm.GetTask(1); err != nil || !ok || task.TaskName != "old retro" {
+		t.Errorf("GetTask(1) after unarchive = %v, %v, %v", task, ok, err)
+	}
+}
@@ -0,0 +1,136 @@
+package task_manager
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/TaskTrackerCLI/structures"
+)
+
+// ChecklistProgress - сколько пунктов чек-листа таска отмечено и сколько их всего
+func ChecklistProgress(task structures.Task) (int, int) {
+	done := 0
+	for _, item := range task.TaskChecklist {
+		if item.Done {
+			done++
+		}
+	}
+	return done, len(task.TaskChecklist)
+}
+
+// AddCheckItem - добавляет неотмеченный пункт в конец чек-листа таска. false, если таска нет
+func (taskManager *TaskManager) AddCheckItem(id int, text string) (bool, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return false, fmt.Errorf("checklist item must not be empty")
+	}
+	return taskManager.changeChecklist(id, func(store Store, task *structures.Task) error {
+		task.TaskChecklist = append(task.TaskChecklist, structures.ChecklistItem{Text: text})
+		return nil
+	})
+}
+
+// AutoDone - итог перевода в DONE таска, у которого после изменения чек-листа отмечены все пункты
+type AutoDone struct {
+	// Completed - таск переведен в DONE
+	Completed bool
+	// Skipped - почему таск остался в прежнем статусе: ошибка с ErrTransitionNotAllowed или ErrOpenSubtasks.
+	// nil, если переводить было нечего
+	Skipped error
+}
+
+// ToggleCheckItem - отмечает n-й пункт чек-листа (с 1) или снимает отметку. С autoDone таск, у которого
+// отмечены все пункты, переводится в DONE (см. completeChecklist). Возвращает, найден ли таск
+func (taskManager *TaskManager) ToggleCheckItem(id, n int, autoDone bool) (bool, AutoDone, error) {
+	var result AutoDone
+	found, err := taskManager.changeChecklist(id, func(store Store, task *structures.Task) error {
+		if err := checkItemExists(*task, n); err != nil {
+			return err
+		}
+		task.TaskChecklist[n-1].Done = !task.TaskChecklist[n-1].Done
+		if !autoDone {
+			return nil
+		}
+		var err error
+		result, err = taskManager.completeChecklist(store, task, time.Now())
+		return err
+	})
+	return found, result, err
+}
+
+// RemoveCheckItem - удаляет n-й пункт чек-листа (с 1), следующие пункты сдвигаются. Если после удаления
+// отмечены все оставшиеся пункты, с autoDone таск переводится в DONE, как в ToggleCheckItem
+func (taskManager *TaskManager) RemoveCheckItem(id, n int, autoDone bool) (bool, AutoDone, error) {
+	var result AutoDone
+	found, err := taskManager.changeChecklist(id, func(store Store, task *structures.Task) error {
+		if err := checkItemExists(*task, n); err != nil {
+			return err
+		}
+		checklist := make([]structures.ChecklistItem, 0, len(task.TaskChecklist)-1)
+		checklist = append(checklist, task.TaskChecklist[:n-1]...)
+		task.TaskChecklist = append(checklist, task.TaskChecklist[n:]...)
+		if len(task.TaskChecklist) == 0 {
+			task.TaskChecklist = nil
+		}
+		if !autoDone {
+			return nil
+		}
+		var err error
+		result, err = taskManager.completeChecklist(store, task, time.Now())
+		return err
+	})
+	return found, result, err
+}
+
+func checkItemExists(task structures.Task, n int) error {
+	if n < 1 || n > len(task.TaskChecklist) {
+		return fmt.Errorf("task %d has no checklist item %d, it has %d", task.TaskId, n, len(task.TaskChecklist))
+	}
+	return nil
+}
+
+func (taskManager *TaskManager) changeChecklist(id int, change func(store Store, task *structures.Task) error) (bool, error) {
+	var found bool
+	err := taskManager.mutate(OperationChecklist, func(store Store) error {
+		task, ok, err := getActiveTask(store, id)
+		if err != nil || !ok {
+			return err
+		}
+		found = true
+		if err := change(store, &task); err != nil {
+			return err
+		}
+		task.TaskUpdatedAt = time.Now().Format(time.RFC3339)
+		return store.Put(task)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to save checklist: %w", err)
+	}
+	return found, nil
+}
+
+// completeChecklist - переводит в DONE по правилам SetStatus таск, у которого отмечены все пункты чек-листа.
+// Если рабочий процесс не разрешает переход или есть открытые подзадачи, статус не меняется, а причина
+// возвращается в AutoDone.Skipped: изменение чек-листа при этом все равно сохраняется
+func (taskManager *TaskManager) completeChecklist(store Store, task *structures.Task, now time.Time) (AutoDone, error) {
+	done, total := ChecklistProgress(*task)
+	if total == 0 || done < total || task.TaskStatus == StatusDone {
+		return AutoDone{}, nil
+	}
+	if err := taskManager.Workflow.checkTransition(task.TaskId, task.TaskStatus, StatusDone); err != nil {
+		return AutoDone{Skipped: err}, nil
+	}
+	open, err := openSubtasks(store, task.TaskId)
+	if err != nil {
+		return AutoDone{}, err
+	}
+	if len(open) > 0 {
+		return AutoDone{Skipped: fmt.Errorf("%w: %d of them are not DONE", ErrOpenSubtasks, len(open))}, nil
+	}
+	task.TaskStatus = StatusDone
+	if _, err := scheduleNextOccurrence(store, task, now); err != nil {
+		return AutoDone{}, err
+	}
+	return AutoDone{Completed: true}, nil
+}
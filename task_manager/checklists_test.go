@@ -0,0 +1,137 @@
+package task_manager
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+// TestChecklists - проверяет добавление, отметку и удаление пунктов чек-листа, проверку номера пункта
+// и перевод таска в DONE, когда с autoDone отмечены все пункты.
+func TestChecklists(t *testing.T) {
+	tm, err := NewTaskManagerWithStore(NewMemoryStore())
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+	id, err := tm.AddTask("move flat", "")
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	progress := func() (int, int) {
+		t.Helper()
+		task, _, err := tm.GetTask(id)
+		if err != nil {
+			t.Fatalf("GetTask() error = %v", err)
+		}
+		return ChecklistProgress(task)
+	}
+
+	if _, err := tm.AddCheckItem(id, " "); err == nil {
+		t.Errorf("AddCheckItem() with an empty text error = nil")
+	}
+	if ok, err := tm.AddCheckItem(42, "boxes"); ok || err != nil {
+		t.Errorf("AddCheckItem() on a missing task = %v, %v, want false, nil", ok, err)
+	}
+	for _, text := range []string{"boxes", "movers", "keys"} {
+		if ok, err := tm.AddCheckItem(id, text); !ok || err != nil {
+			t.Fatalf("AddCheckItem(%q) = %v, %v", text, ok, err)
+		}
+	}
+	for _, n := range []int{0, 4} {
+		if _, _, err := tm.ToggleCheckItem(id, n, false); err == nil {
+			t.Errorf("ToggleCheckItem(%d) error = nil", n)
+		}
+	}
+
+	if ok, result, err := tm.ToggleCheckItem(id, 1, true); !ok || result != (AutoDone{}) || err != nil {
+		t.Fatalf("ToggleCheckItem(1) = %v, %+v, %v, want true, nothing to do, nil", ok, result, err)
+	}
+	if ok, result, err := tm.RemoveCheckItem(id, 2, true); !ok || result.Completed || err != nil {
+		t.Fatalf("RemoveCheckItem(2) = %v, %+v, %v", ok, result, err)
+	}
+	if done, total := progress(); done != 1 || total != 2 {
+		t.Errorf("progress = %d/%d, want 1/2", done, total)
+	}
+	if task, _, _ := tm.GetTask(id); task.TaskChecklist[1].Text != "keys" {
+		t.Errorf("after RemoveCheckItem() item 2 = %q, want keys", task.TaskChecklist[1].Text)
+	}
+
+	// без autoDone таск остается открытым, даже если отмечено все
+	if _, result, err := tm.ToggleCheckItem(id, 2, false); result.Completed || err != nil {
+		t.Fatalf("ToggleCheckItem(2) = %+v, %v", result, err)
+	}
+	if task, _, _ := tm.GetTask(id); task.TaskStatus != StatusTodo {
+		t.Errorf("status without autoDone = %s, want TODO", task.TaskStatus)
+	}
+	if _, _, err := tm.ToggleCheckItem(id, 2, false); err != nil {
+		t.Fatalf("ToggleCheckItem(2) error = %v", err)
+	}
+	if done, _ := progress(); done != 1 {
+		t.Errorf("second toggle left %d items done, want 1", done)
+	}
+	if _, result, err := tm.ToggleCheckItem(id, 2, true); !result.Completed || err != nil {
+		t.Fatalf("ToggleCheckItem(2) with autoDone = %+v, %v, want completed", result, err)
+	}
+	if task, _, _ := tm.GetTask(id); task.TaskStatus != StatusDone {
+		t.Errorf("status with autoDone = %s, want DONE", task.TaskStatus)
+	}
+
+	// открытая подзадача не дает завершить таск, но отметка пункта сохраняется
+	parent, _ := tm.AddTask("release", "")
+	if _, err := tm.AddTaskWithValues("changelog", "", map[string]string{"task_parent": strconv.Itoa(parent)}); err != nil {
+		t.Fatalf("AddTaskWithValues() error = %v", err)
+	}
+	if _, err := tm.AddCheckItem(parent, "tag"); err != nil {
+		t.Fatalf("AddCheckItem() error = %v", err)
+	}
+	if _, result, err := tm.ToggleCheckItem(parent, 1, true); result.Completed || !errors.Is(result.Skipped, ErrOpenSubtasks) || err != nil {
+		t.Errorf("ToggleCheckItem() with open subtasks = %+v, %v, want skipped with ErrOpenSubtasks", result, err)
+	}
+	if task, _, _ := tm.GetTask(parent); task.TaskStatus != StatusTodo || !task.TaskChecklist[0].Done {
+		t.Errorf("task with open subtasks = %s, item done %v, want TODO and done", task.TaskStatus, task.TaskChecklist[0].Done)
+	}
+}
+
+// TestRemoveCheckItemCompletes - удаление последнего неотмеченного пункта завершает таск так же, как его
+// отметка, а запрет рабочего процесса возвращается как причина, по которой таск остался открытым.
+func TestRemoveCheckItemCompletes(t *testing.T) {
+	tm, err := NewTaskManagerWithStore(NewMemoryStore())
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+	id, _ := tm.AddTask("move", "")
+	for _, text := range []string{"boxes", "movers"} {
+		if _, err := tm.AddCheckItem(id, text); err != nil {
+			t.Fatalf("AddCheckItem() error = %v", err)
+		}
+	}
+	if _, _, err := tm.ToggleCheckItem(id, 1, false); err != nil {
+		t.Fatalf("ToggleCheckItem() error = %v", err)
+	}
+
+	// TODO -> DONE запрещен, поэтому таск остается открытым, но пункт удаляется
+	tm.Workflow = Workflow{
+		Statuses:    []string{StatusTodo, StatusInProgress, StatusDone},
+		Transitions: map[string][]string{StatusTodo: {StatusInProgress}, StatusInProgress: {StatusDone}},
+	}
+	ok, result, err := tm.RemoveCheckItem(id, 2, true)
+	if !ok || err != nil || result.Completed || !errors.Is(result.Skipped, ErrTransitionNotAllowed) {
+		t.Fatalf("RemoveCheckItem() with a forbidden transition = %v, %+v, %v, want skipped with ErrTransitionNotAllowed", ok, result, err)
+	}
+	if task, _, _ := tm.GetTask(id); task.TaskStatus != StatusTodo || len(task.TaskChecklist) != 1 {
+		t.Errorf("task = %s with %d items, want TODO with 1 item", task.TaskStatus, len(task.TaskChecklist))
+	}
+
+	if _, _, err := tm.SetStatus(id, StatusInProgress); err != nil {
+		t.Fatalf("SetStatus(IN_PROGRESS) error = %v", err)
+	}
+	if _, err := tm.AddCheckItem(id, "keys"); err != nil {
+		t.Fatalf("AddCheckItem() error = %v", err)
+	}
+	if _, result, err := tm.RemoveCheckItem(id, 2, true); !result.Completed || result.Skipped != nil || err != nil {
+		t.Fatalf("RemoveCheckItem() of the last open item = %+v, %v, want completed", result, err)
+	}
+	if task, _, _ := tm.GetTask(id); task.TaskStatus != StatusDone {
+		t.Errorf("status after removing the last open item = %s, want DONE", task.TaskStatus)
+	}
+}
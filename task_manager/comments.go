@@ -0,0 +1,47 @@
+package task_manager
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/TaskTrackerCLI/structures"
+)
+
+// AddComment - дописывает к таску заметку text от имени Actor. Заметки не меняются и не удаляются,
+// поэтому описание таска можно не переписывать ради нового контекста. false, если таска нет
+func (taskManager *TaskManager) AddComment(id int, text string) (bool, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return false, fmt.Errorf("comment must not be empty")
+	}
+	var found bool
+	err := taskManager.mutate(OperationComment, func(store Store) error {
+		task, ok, err := getActiveTask(store, id)
+		if err != nil || !ok {
+			return err
+		}
+		found = true
+		now := time.Now().Format(time.RFC3339)
+		task.TaskComments = append(task.TaskComments, structures.Comment{
+			At:    now,
+			Actor: taskManager.Actor,
+			Text:  text,
+		})
+		task.TaskUpdatedAt = now
+		return store.Put(task)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to save comment: %w", err)
+	}
+	return found, nil
+}
+
+// Comments - заметки таска в порядке добавления. false, если таска нет
+func (taskManager *TaskManager) Comments(id int) ([]structures.Comment, bool, error) {
+	task, ok, err := taskManager.GetTask(id)
+	if err != nil || !ok {
+		return nil, ok, err
+	}
+	return task.TaskComments, true, nil
+}
@@ -0,0 +1,61 @@
+package task_manager
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+// TestComments - проверяет, что заметки дописываются по порядку с автором, не затирают описание,
+// сохраняются в файле и отменяются через undo.
+func TestComments(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	tm, err := NewTaskManager(path)
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+	tm.Actor = "alice"
+	id, err := tm.AddTask("migrate db", "move to postgres")
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+
+	if _, err := tm.AddComment(id, "   "); err == nil {
+		t.Errorf("AddComment() with an empty text error = nil")
+	}
+	if ok, err := tm.AddComment(42, "hello"); ok || err != nil {
+		t.Errorf("AddComment() on a missing task = %v, %v, want false, nil", ok, err)
+	}
+	for _, text := range []string{"schema dumped", " waiting for DBA review "} {
+		if ok, err := tm.AddComment(id, text); !ok || err != nil {
+			t.Fatalf("AddComment(%q) = %v, %v", text, ok, err)
+		}
+	}
+
+	reopened, err := NewTaskManager(path)
+	if err != nil {
+		t.Fatalf("Failed to reopen TaskManager: %v", err)
+	}
+	comments, ok, err := reopened.Comments(id)
+	if !ok || err != nil {
+		t.Fatalf("Comments() = %v, %v", ok, err)
+	}
+	if len(comments) != 2 || comments[0].Text != "schema dumped" || comments[1].Text != "waiting for DBA review" {
+		t.Fatalf("Comments() = %+v, want both comments in order", comments)
+	}
+	if comments[0].Actor != "alice" || comments[0].At == "" {
+		t.Errorf("comment author = %q at %q, want alice with a timestamp", comments[0].Actor, comments[0].At)
+	}
+	if task, _, _ := reopened.GetTask(id); task.TaskDescription != "move to postgres" {
+		t.Errorf("TaskDescription = %q, comments must not change it", task.TaskDescription)
+	}
+	if _, ok, _ := reopened.Comments(42); ok {
+		t.Errorf("Comments() on a missing task ok = true")
+	}
+
+	if _, _, err := reopened.Undo(); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if comments, _, _ := reopened.Comments(id); len(comments) != 1 {
+		t.Errorf("after Undo() task has %d comments, want 1", len(comments))
+	}
+}
@@ -0,0 +1,91 @@
+package task_manager
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+// TestTaskManagerConcurrentUse - нагружает один TaskManager из множества горутин.
+// Имеет смысл запускать с go test -race.
+func TestTaskManagerConcurrentUse(t *testing.T) {
+	stores := map[string]func(t *testing.T) Store{
+		"MemoryStore": func(t *testing.T) Store {
+			return NewMemoryStore()
+		},
+		"JSONFileStore": func(t *testing.T) Store {
+			return NewJSONFileStore(filepath.Join(t.TempDir(), "tasks.json"))
+		},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			tm, err := NewTaskManagerWithStore(newStore(t))
+			if err != nil {
+				t.Fatalf("Failed to create TaskManager: %v", err)
+			}
+
+			const workers = 8
+			const tasksPerWorker = 15
+
+			var wg sync.WaitGroup
+			errs := make(chan error, workers*tasksPerWorker*4)
+			for w := 0; w < workers; w++ {
+				wg.Add(1)
+				go func(w int) {
+					defer wg.Done()
+					for i := 0; i < tasksPerWorker; i++ {
+						id, err := tm.AddTask(fmt.Sprintf("worker %d task %d", w, i), "concurrent")
+						if err != nil {
+							errs <- err
+							continue
+						}
+						if _, err := tm.UpdateTask(id, map[string]string{"task_description": "updated"}); err != nil {
+							errs <- err
+						}
+						if _, _, err := tm.MarkTaskAsInProgress(id); err != nil {
+							errs <- err
+						}
+						if task, ok, err := tm.GetTask(id); err != nil || !ok || task.TaskDescription != "updated" {
+							errs <- fmt.Errorf("GetTask(%d) = %+v, %v, %v", id, task, ok, err)
+						}
+					}
+				}(w)
+
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := 0; i < tasksPerWorker; i++ {
+						if _, err := tm.ListAllTasks(); err != nil {
+							errs <- err
+						}
+						if _, err := tm.SearchTasks("worker"); err != nil {
+							errs <- err
+						}
+					}
+				}()
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				t.Errorf("concurrent operation failed: %v", err)
+			}
+
+			tasks, err := tm.ListInProgressTasks()
+			if err != nil {
+				t.Fatalf("ListInProgressTasks() error = %v", err)
+			}
+			if len(tasks) != workers*tasksPerWorker {
+				t.Errorf("got %d tasks, want %d", len(tasks), workers*tasksPerWorker)
+			}
+			seen := make(map[int]bool)
+			for _, task := range tasks {
+				if seen[task.TaskId] {
+					t.Errorf("duplicate task id %d", task.TaskId)
+				}
+				seen[task.TaskId] = true
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package task_manager
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TaskTrackerCLI/structures"
+)
+
+// AddDependency - отмечает, что таск id нельзя начинать, пока не завершен blockerId.
+// Зависимость, замыкающая цикл, не добавляется. false, если одного из тасков нет
+func (taskManager *TaskManager) AddDependency(id, blockerId int) (bool, error) {
+	if id == blockerId {
+		return false, fmt.Errorf("task %d cannot depend on itself", id)
+	}
+	var found bool
+	err := taskManager.mutate(OperationDepend, func(store Store) error {
+		task, ok, err := getActiveTask(store, id)
+		if err != nil || !ok {
+			return err
+		}
+		if _, ok, err := getActiveTask(store, blockerId); err != nil || !ok {
+			return err
+		}
+		found = true
+		index := sort.SearchInts(task.TaskBlockedBy, blockerId)
+		if index < len(task.TaskBlockedBy) && task.TaskBlockedBy[index] == blockerId {
+			return nil
+		}
+		tasks, err := listUnarchived(store)
+		if err != nil {
+			return err
+		}
+		if path := dependencyPath(tasks, blockerId, id); path != nil {
+			return fmt.Errorf("dependency would create a cycle: %s", formatPath(append([]int{id}, path...)))
+		}
+		blockedBy := make([]int, 0, len(task.TaskBlockedBy)+1)
+		blockedBy = append(blockedBy, task.TaskBlockedBy[:index]...)
+		blockedBy = append(blockedBy, blockerId)
+		task.TaskBlockedBy = append(blockedBy, task.TaskBlockedBy[index:]...)
+		task.TaskUpdatedAt = time.Now().Format(time.RFC3339)
+		return store.Put(task)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to save dependency: %w", err)
+	}
+	return found, nil
+}
+
+// RemoveDependency - снимает зависимость таска id от blockerId. false, если таска id нет
+func (taskManager *TaskManager) RemoveDependency(id, blockerId int) (bool, error) {
+	var found bool
+	err := taskManager.mutate(OperationDepend, func(store Store) error {
+		task, ok, err := getActiveTask(store, id)
+		if err != nil || !ok {
+			return err
+		}
+		found = true
+		index := sort.SearchInts(task.TaskBlockedBy, blockerId)
+		if index == len(task.TaskBlockedBy) || task.TaskBlockedBy[index] != blockerId {
+			return nil
+		}
+		blockedBy := make([]int, 0, len(task.TaskBlockedBy)-1)
+		blockedBy = append(blockedBy, task.TaskBlockedBy[:index]...)
+		blockedBy = append(blockedBy, task.TaskBlockedBy[index+1:]...)
+		if len(blockedBy) == 0 {
+			blockedBy = nil
+		}
+		task.TaskBlockedBy = blockedBy
+		task.TaskUpdatedAt = time.Now().Format(time.RFC3339)
+		return store.Put(task)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to save dependency: %w", err)
+	}
+	return found, nil
+}
+
+// dependencyPath - цепочка зависимостей от таска from до таска to, nil если to не достижим
+func dependencyPath(tasks []structures.Task, from, to int) []int {
+	blockedBy := make(map[int][]int, len(tasks))
+	for _, task := range tasks {
+		blockedBy[task.TaskId] = task.TaskBlockedBy
+	}
+	visited := make(map[int]bool)
+	var walk func(id int) []int
+	walk = func(id int) []int {
+		if id == to {
+			return []int{id}
+		}
+		if visited[id] {
+			return nil
+		}
+		visited[id] = true
+		for _, next := range blockedBy[id] {
+			if path := walk(next); path != nil {
+				return append([]int{id}, path...)
+			}
+		}
+		return nil
+	}
+	return walk(from)
+}
+
+func formatPath(ids []int) string {
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = strconv.Itoa(id)
+	}
+	return strings.Join(values, " -> ")
+}
+
+// openBlockers - для каждого заблокированного таска id тасков, которые его блокируют. Блокируют только
+// невыполненные таски рабочего набора: архивные считаются выполненными, таски из корзины не учитываются,
+// поэтому достаточно передать рабочий набор
+func openBlockers(tasks []structures.Task) map[int][]int {
+	open := make(map[int]bool, len(tasks))
+	for _, task := range tasks {
+		open[task.TaskId] = isActive(task) && task.TaskStatus != StatusDone
+	}
+	blockers := make(map[int][]int)
+	for _, task := range tasks {
+		for _, blockerId := range task.TaskBlockedBy {
+			if open[blockerId] {
+				blockers[task.TaskId] = append(blockers[task.TaskId], blockerId)
+			}
+		}
+	}
+	return blockers
+}
+
+// taskBlockers - id незавершенных тасков, от которых зависит таск id
+func taskBlockers(store Store, id int) ([]int, error) {
+	tasks, err := listActive(store)
+	if err != nil {
+		return nil, err
+	}
+	return openBlockers(tasks)[id], nil
+}
+
+// Blockers - заблокированные таски: по id таска id еще не завершенных тасков, от которых он зависит
+func (taskManager *TaskManager) Blockers() (map[int][]int, error) {
+	taskManager.mu.RLock()
+	defer taskManager.mu.RUnlock()
+	tasks, err := listActive(taskManager.store)
+	if err != nil {
+		return nil, err
+	}
+	return openBlockers(tasks), nil
+}
+
+// ListReadyTasks - таски в статусе TODO, которые ничего не ждут и их можно брать в работу
+func (taskManager *TaskManager) ListReadyTasks() ([]structures.Task, error) {
+	blockers, err := taskManager.Blockers()
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := taskManager.ListTodoTasks()
+	if err != nil {
+		return nil, err
+	}
+	ready := tasks[:0]
+	for _, task := range tasks {
+		if len(blockers[task.TaskId]) == 0 {
+			ready = append(ready, task)
+		}
+	}
+	return ready, nil
+}
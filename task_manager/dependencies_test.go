@@ -0,0 +1,107 @@
+package task_manager
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestDependencies - проверяет добавление и снятие зависимостей, обнаружение циклов,
+// вычисление заблокированных тасков и список готовых к работе.
+func TestDependencies(t *testing.T) {
+	tm, err := NewTaskManagerWithStore(NewMemoryStore())
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+	for i := 0; i < 4; i++ {
+		if _, err := tm.AddTask("task", ""); err != nil {
+			t.Fatalf("AddTask() error = %v", err)
+		}
+	}
+
+	// 3 ждет 2, 2 ждет 1
+	for _, edge := range [][2]int{{3, 2}, {2, 1}, {3, 2}} {
+		if ok, err := tm.AddDependency(edge[0], edge[1]); !ok || err != nil {
+			t.Fatalf("AddDependency(%d, %d) = %v, %v", edge[0], edge[1], ok, err)
+		}
+	}
+	if task, _, _ := tm.GetTask(3); len(task.TaskBlockedBy) != 1 {
+		t.Errorf("duplicate dependency was stored: %v", task.TaskBlockedBy)
+	}
+
+	tests := []struct {
+		name          string
+		id, blockerId int
+		wantOk        bool
+		wantErr       string
+	}{
+		{name: "Self dependency", id: 1, blockerId: 1, wantErr: "itself"},
+		{name: "Direct cycle", id: 1, blockerId: 2, wantErr: "1 -> 2 -> 1"},
+		{name: "Transitive cycle", id: 1, blockerId: 3, wantErr: "1 -> 3 -> 2 -> 1"},
+		{name: "Missing blocker", id: 1, blockerId: 42, wantOk: false},
+		{name: "Missing task", id: 42, blockerId: 1, wantOk: false},
+		{name: "Independent edge", id: 4, blockerId: 3, wantOk: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ok, err := tm.AddDependency(tt.id, tt.blockerId)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("AddDependency() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || ok != tt.wantOk {
+				t.Errorf("AddDependency() = %v, %v, want %v, nil", ok, err, tt.wantOk)
+			}
+		})
+	}
+
+	blockers, err := tm.Blockers()
+	if err != nil {
+		t.Fatalf("Blockers() error = %v", err)
+	}
+	if len(blockers) != 3 || blockers[3][0] != 2 {
+		t.Errorf("Blockers() = %v, want 2, 3 and 4 blocked", blockers)
+	}
+	if ready, _ := tm.ListReadyTasks(); len(ready) != 1 || ready[0].TaskId != 1 {
+		t.Errorf("ListReadyTasks() = %+v, want only task 1", ready)
+	}
+
+	// взять заблокированный таск в работу можно, но SetStatus сообщает, кого он ждет
+	if ok, blocked, err := tm.MarkTaskAsInProgress(2); !ok || err != nil || len(blocked) != 1 || blocked[0] != 1 {
+		t.Errorf("MarkTaskAsInProgress() of a blocked task = %v, %v, %v, want true, [1], nil", ok, blocked, err)
+	}
+	if task, _, _ := tm.GetTask(2); task.TaskStatus != StatusInProgress {
+		t.Errorf("blocked task status = %s, want IN_PROGRESS", task.TaskStatus)
+	}
+	if _, change, err := tm.SetStatus(2, StatusTodo); len(change.Blockers) != 0 || err != nil {
+		t.Errorf("SetStatus(TODO) = %v, %v, want no blockers", change.Blockers, err)
+	}
+	if _, blocked, _ := tm.StartTimer(4); len(blocked) != 1 {
+		t.Errorf("StartTimer() of a blocked task reported blockers %v, want one", blocked)
+	}
+	if _, _, err := tm.StopTimer(); err != nil {
+		t.Fatalf("StopTimer() error = %v", err)
+	}
+	if _, err := tm.MarkTaskAsTodo(4); err != nil {
+		t.Fatalf("MarkTaskAsTodo() error = %v", err)
+	}
+
+	// выполненный блокер и блокер в корзине больше не мешают
+	if _, err := tm.MarkTaskAsDone(1); err != nil {
+		t.Fatalf("MarkTaskAsDone() error = %v", err)
+	}
+	if _, err := tm.DeleteTask(3); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	if ready, _ := tm.ListReadyTasks(); len(ready) != 2 {
+		t.Errorf("ListReadyTasks() = %+v, want tasks 2 and 4", ready)
+	}
+
+	if ok, err := tm.RemoveDependency(2, 1); !ok || err != nil {
+		t.Fatalf("RemoveDependency() = %v, %v", ok, err)
+	}
+	if task, _, _ := tm.GetTask(2); task.TaskBlockedBy != nil {
+		t.Errorf("after RemoveDependency task 2 is blocked by %v", task.TaskBlockedBy)
+	}
+}
@@ -0,0 +1,63 @@
+package task_manager
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TaskTrackerCLI/dates"
+	"github.com/TaskTrackerCLI/structures"
+)
+
+// parseDue - срок из пользовательского ввода в формате хранения. Пустая строка или none снимает срок
+func parseDue(value string, now time.Time) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.EqualFold(value, "none") {
+		return "", nil
+	}
+	due, err := dates.Parse(value, now)
+	if err != nil {
+		return "", err
+	}
+	return dates.Format(due), nil
+}
+
+// DueDate - срок таска, false если срока нет или он не разбирается
+func DueDate(task structures.Task, loc *time.Location) (time.Time, bool) {
+	if task.TaskDueDate == "" {
+		return time.Time{}, false
+	}
+	due, err := dates.ParseStored(task.TaskDueDate, loc)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return due, true
+}
+
+// Agenda - невыполненные таски со сроком, разложенные по разделам повестки относительно now.
+// Внутри раздела таски отсортированы по сроку, затем по приоритету
+func (taskManager *TaskManager) Agenda(now time.Time) (map[dates.Bucket][]structures.Task, error) {
+	tasks, err := taskManager.ListAllTasks()
+	if err != nil {
+		return nil, err
+	}
+	agenda := make(map[dates.Bucket][]structures.Task)
+	for _, task := range tasks {
+		if task.TaskStatus == StatusDone {
+			continue
+		}
+		due, ok := DueDate(task, now.Location())
+		if !ok {
+			continue
+		}
+		bucket := dates.BucketOf(due, now)
+		agenda[bucket] = append(agenda[bucket], task)
+	}
+	for _, bucket := range agenda {
+		// ListAllTasks уже отсортировал по приоритету, стабильная сортировка его сохранит
+		sort.SliceStable(bucket, func(i, j int) bool {
+			return bucket[i].TaskDueDate < bucket[j].TaskDueDate
+		})
+	}
+	return agenda, nil
+}
@@ -0,0 +1,71 @@
+package task_manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TaskTrackerCLI/dates"
+)
+
+// TestAgenda - проверяет задание срока в разных форматах и раскладку невыполненных тасков по разделам повестки.
+func TestAgenda(t *testing.T) {
+	tm, err := NewTaskManagerWithStore(NewMemoryStore())
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		due     string
+		wantErr bool
+	}{
+		{name: "Overdue", due: "2020-01-01"},
+		{name: "Today", due: "today"},
+		{name: "Soon", due: "+3d"},
+		{name: "Later", due: "+1m"},
+		{name: "Overdue but done", due: "2020-01-01"},
+		{name: "No due date"},
+		{name: "Invalid", due: "someday", wantErr: true},
+	}
+	for _, tt := range tests {
+		values := map[string]string{}
+		if tt.due != "" {
+			values["task_due"] = tt.due
+		}
+		if _, err := tm.AddTaskWithValues(tt.name, "", values); (err != nil) != tt.wantErr {
+			t.Fatalf("AddTaskWithValues(due %q) error = %v, wantErr %v", tt.due, err, tt.wantErr)
+		}
+	}
+	if _, err := tm.MarkTaskAsDone(5); err != nil {
+		t.Fatalf("MarkTaskAsDone() error = %v", err)
+	}
+
+	agenda, err := tm.Agenda(time.Now())
+	if err != nil {
+		t.Fatalf("Agenda() error = %v", err)
+	}
+	want := map[dates.Bucket][]int{
+		dates.Overdue:  {1},
+		dates.Today:    {2},
+		dates.ThisWeek: {3},
+		dates.Later:    {4},
+	}
+	for _, bucket := range dates.Buckets {
+		got := agenda[bucket]
+		if len(got) != len(want[bucket]) {
+			t.Fatalf("%s = %+v, want ids %v", bucket, got, want[bucket])
+		}
+		for i, task := range got {
+			if task.TaskId != want[bucket][i] {
+				t.Errorf("%s[%d] = %d, want %d", bucket, i, task.TaskId, want[bucket][i])
+			}
+		}
+	}
+
+	if _, err := tm.UpdateTask(1, map[string]string{"task_due": "none"}); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if task, _, _ := tm.GetTask(1); task.TaskDueDate != "" {
+		t.Errorf("due date after clearing = %q, want empty", task.TaskDueDate)
+	}
+}
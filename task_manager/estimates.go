@@ -0,0 +1,227 @@
+package task_manager
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TaskTrackerCLI/structures"
+)
+
+// pointsSuffixes - допустимые окончания оценки в story points, первое используется в канонической записи
+var pointsSuffixes = []string{"pt", "pts", "sp", "p"}
+
+// Estimate - оценка трудоемкости таска: задано либо Points, либо Duration
+type Estimate struct {
+	Points   float64
+	Duration time.Duration
+}
+
+// IsPoints - оценка в story points, а не во времени
+func (estimate Estimate) IsPoints() bool {
+	return estimate.Points > 0
+}
+
+// String - каноническая запись оценки: 3pt, 0.5pt, 1h30m, 45m
+func (estimate Estimate) String() string {
+	if estimate.IsPoints() {
+		return strconv.FormatFloat(estimate.Points, 'f', -1, 64) + pointsSuffixes[0]
+	}
+	hours, minutes := int(estimate.Duration/time.Hour), int(estimate.Duration%time.Hour/time.Minute)
+	switch {
+	case hours > 0 && minutes > 0:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dh", hours)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
+
+// ParseEstimate - разбирает оценку: число story points (3, 3pt, 0.5sp) или длительность (2h, 1h30m, 45m)
+// с точностью до минуты
+func ParseEstimate(value string) (Estimate, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	number := value
+	for _, suffix := range pointsSuffixes {
+		if trimmed, ok := strings.CutSuffix(value, suffix); ok {
+			number = trimmed
+			break
+		}
+	}
+	if points, err := strconv.ParseFloat(strings.TrimSpace(number), 64); err == nil {
+		// ParseFloat понимает nan и inf, а такая оценка не выводится и выпадает из отчета
+		if math.IsNaN(points) || math.IsInf(points, 0) {
+			return Estimate{}, fmt.Errorf("estimate must be a finite number, got %q", value)
+		}
+		if points <= 0 {
+			return Estimate{}, fmt.Errorf("estimate must be positive, got %q", value)
+		}
+		return Estimate{Points: points}, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return Estimate{}, fmt.Errorf("invalid estimate %q, use story points (3, 3pt) or a duration (2h, 1h30m, 45m)", value)
+	}
+	duration = duration.Round(time.Minute)
+	if duration <= 0 {
+		return Estimate{}, fmt.Errorf("estimate must be at least a minute, got %q", value)
+	}
+	return Estimate{Duration: duration}, nil
+}
+
+// parseEstimate - оценка из пользовательского ввода в формате хранения. Пустая строка или none снимает оценку
+func parseEstimate(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.EqualFold(value, "none") {
+		return "", nil
+	}
+	estimate, err := ParseEstimate(value)
+	if err != nil {
+		return "", err
+	}
+	return estimate.String(), nil
+}
+
+// ActualTime - время от первого перехода таска в IN_PROGRESS до последнего перехода в DONE по истории таска.
+// false, если таск не выполнен или не проходил через IN_PROGRESS
+func ActualTime(task structures.Task) (time.Duration, bool) {
+	if task.TaskStatus != StatusDone {
+		return 0, false
+	}
+	var start, end time.Time
+	for _, entry := range task.TaskHistory {
+		if entry.Field != HistoryFieldStatus {
+			continue
+		}
+		at, err := time.Parse(time.RFC3339, entry.At)
+		if err != nil {
+			continue
+		}
+		switch {
+		case entry.NewValue == StatusInProgress && start.IsZero():
+			start = at
+		case entry.NewValue == StatusDone && !start.IsZero():
+			end = at
+		}
+	}
+	if start.IsZero() || end.IsZero() || end.Before(start) {
+		return 0, false
+	}
+	return end.Sub(start), true
+}
+
+// EstimateAccuracy - оценка выполненного таска и фактическое время работы над ним
+type EstimateAccuracy struct {
+	Task     structures.Task
+	Estimate Estimate
+	Actual   time.Duration
+}
+
+// Ratio - отношение факта к оценке во времени: 1 - точно, больше 1 - недооценили. 0 для оценок в story points
+func (row EstimateAccuracy) Ratio() float64 {
+	if row.Estimate.IsPoints() {
+		return 0
+	}
+	return float64(row.Actual) / float64(row.Estimate.Duration)
+}
+
+// TimePerPoint - сколько времени занял один story point, 0 для оценок во времени
+func (row EstimateAccuracy) TimePerPoint() time.Duration {
+	if !row.Estimate.IsPoints() {
+		return 0
+	}
+	return time.Duration(float64(row.Actual) / row.Estimate.Points)
+}
+
+// EstimateReport - сравнение оценок с фактическим временем по выполненным таскам
+type EstimateReport struct {
+	// Tasks - выполненные таски с оценкой и измеренным временем, по id
+	Tasks []EstimateAccuracy
+	// Unmeasured - выполненные таски с оценкой, которые не проходили через IN_PROGRESS
+	Unmeasured int
+	// Estimated, Actual - суммы оценок и факта по таскам с оценкой во времени
+	Estimated time.Duration
+	Actual    time.Duration
+	// Points, PointsActual - сумма story points и факт по таскам с оценкой в story points
+	Points       float64
+	PointsActual time.Duration
+}
+
+// Ratio - отношение суммарного факта к суммарной оценке во времени, 0 если таких тасков нет
+func (report EstimateReport) Ratio() float64 {
+	if report.Estimated == 0 {
+		return 0
+	}
+	return float64(report.Actual) / float64(report.Estimated)
+}
+
+// MeanError - средняя относительная ошибка оценок во времени: 0.25 - в среднем ошиблись на 25%
+func (report EstimateReport) MeanError() float64 {
+	var total float64
+	var count int
+	for _, row := range report.Tasks {
+		if row.Estimate.IsPoints() {
+			continue
+		}
+		deviation := row.Ratio() - 1
+		if deviation < 0 {
+			deviation = -deviation
+		}
+		total += deviation
+		count++
+	}
+	if count == 0 {
+		return 0
+	}
+	return total / float64(count)
+}
+
+// TimePerPoint - сколько в среднем занимает один story point, 0 если оценок в story points нет
+func (report EstimateReport) TimePerPoint() time.Duration {
+	if report.Points == 0 {
+		return 0
+	}
+	return time.Duration(float64(report.PointsActual) / report.Points)
+}
+
+// EstimateReport - сравнивает оценки выполненных тасков, включая архив, с фактическим временем
+// между переходами в IN_PROGRESS и DONE. Таски из корзины не учитываются
+func (taskManager *TaskManager) EstimateReport() (EstimateReport, error) {
+	taskManager.mu.RLock()
+	defer taskManager.mu.RUnlock()
+
+	var report EstimateReport
+	tasks, err := taskManager.store.List()
+	if err != nil {
+		return report, err
+	}
+	for _, task := range tasks {
+		if task.TaskEstimate == "" || task.TaskStatus != StatusDone || isTrashed(task) {
+			continue
+		}
+		estimate, err := ParseEstimate(task.TaskEstimate)
+		if err != nil {
+			continue
+		}
+		actual, ok := ActualTime(task)
+		if !ok {
+			report.Unmeasured++
+			continue
+		}
+		report.Tasks = append(report.Tasks, EstimateAccuracy{Task: task, Estimate: estimate, Actual: actual})
+		if estimate.IsPoints() {
+			report.Points += estimate.Points
+			report.PointsActual += actual
+		} else {
+			report.Estimated += estimate.Duration
+			report.Actual += actual
+		}
+	}
+	sort.Slice(report.Tasks, func(i, j int) bool {
+		return report.Tasks[i].Task.TaskId < report.Tasks[j].Task.TaskId
+	})
+	return report, nil
+}
@@ -0,0 +1,120 @@
+package task_manager
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TaskTrackerCLI/structures"
+)
+
+// TestParseEstimate - проверяет разбор оценок в story points и во времени и их каноническую запись.
+func TestParseEstimate(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"3", "3pt"},
+		{"3pt", "3pt"},
+		{" 0.5 SP", "0.5pt"},
+		{"5pts", "5pt"},
+		{"2h", "2h"},
+		{"90m", "1h30m"},
+		{"45m", "45m"},
+	}
+	for _, tt := range tests {
+		estimate, err := ParseEstimate(tt.value)
+		if err != nil {
+			t.Errorf("ParseEstimate(%q) error = %v", tt.value, err)
+			continue
+		}
+		if estimate.String() != tt.want {
+			t.Errorf("ParseEstimate(%q) = %s, want %s", tt.value, estimate, tt.want)
+		}
+	}
+	for _, value := range []string{"", "soon", "0", "-2", "-1h", "10s", "nan", "NaNpt", "inf", "-inf", "+Infinity", "infsp"} {
+		if _, err := ParseEstimate(value); err == nil {
+			t.Errorf("ParseEstimate(%q) error = nil", value)
+		}
+	}
+}
+
+// TestEstimateReport - проверяет, что оценка задается через add/update и что отчет сравнивает ее
+// со временем между переходами в IN_PROGRESS и DONE.
+func TestEstimateReport(t *testing.T) {
+	store := NewMemoryStore()
+	tm, err := NewTaskManagerWithStore(store)
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+	if _, err := tm.AddTaskWithValues("deploy", "", map[string]string{"task_estimate": "someday"}); err == nil {
+		t.Errorf("AddTaskWithValues() with an invalid estimate error = nil")
+	}
+	id, err := tm.AddTaskWithValues("deploy", "", map[string]string{"task_estimate": "120m"})
+	if err != nil {
+		t.Fatalf("AddTaskWithValues() error = %v", err)
+	}
+	if task, _, _ := tm.GetTask(id); task.TaskEstimate != "2h" {
+		t.Errorf("TaskEstimate = %q, want 2h", task.TaskEstimate)
+	}
+	if _, err := tm.UpdateTask(id, map[string]string{"task_estimate": "none"}); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if task, _, _ := tm.GetTask(id); task.TaskEstimate != "" {
+		t.Errorf("TaskEstimate after none = %q, want empty", task.TaskEstimate)
+	}
+
+	start := time.Date(2026, time.March, 2, 9, 0, 0, 0, time.UTC)
+	transitions := func(times ...time.Time) []structures.HistoryEntry {
+		var history []structures.HistoryEntry
+		for i, at := range times {
+			status := StatusInProgress
+			if i%2 == 1 {
+				status = StatusDone
+			}
+			history = append(history, structures.HistoryEntry{At: at.Format(time.RFC3339), Field: HistoryFieldStatus, NewValue: status})
+		}
+		return history
+	}
+	tasks := []structures.Task{
+		{TaskId: 10, TaskName: "api", TaskStatus: StatusDone, TaskEstimate: "2h",
+			TaskHistory: transitions(start, start.Add(3*time.Hour))},
+		// после повторного открытия считается время до последнего DONE
+		{TaskId: 11, TaskName: "docs", TaskStatus: StatusDone, TaskEstimate: "2h",
+			TaskHistory: transitions(start, start.Add(30*time.Minute), start.Add(time.Hour), start.Add(time.Hour))},
+		{TaskId: 12, TaskName: "ui", TaskStatus: StatusDone, TaskEstimate: "2pt", TaskArchivedAt: start.Format(time.RFC3339),
+			TaskHistory: transitions(start, start.Add(4*time.Hour))},
+		{TaskId: 13, TaskName: "skipped", TaskStatus: StatusDone, TaskEstimate: "1h"},
+		{TaskId: 14, TaskName: "open", TaskStatus: StatusInProgress, TaskEstimate: "1h",
+			TaskHistory: transitions(start)},
+		{TaskId: 15, TaskName: "trashed", TaskStatus: StatusDone, TaskEstimate: "1h", TaskDeletedAt: start.Format(time.RFC3339),
+			TaskHistory: transitions(start, start.Add(time.Hour))},
+	}
+	for _, task := range tasks {
+		if err := store.Put(task); err != nil {
+			t.Fatalf("Put() error = %v", err)
+		}
+	}
+
+	report, err := tm.EstimateReport()
+	if err != nil {
+		t.Fatalf("EstimateReport() error = %v", err)
+	}
+	if len(report.Tasks) != 3 || report.Tasks[0].Task.TaskId != 10 || report.Tasks[2].Task.TaskId != 12 {
+		t.Fatalf("Tasks = %+v, want tasks 10, 11 and 12", report.Tasks)
+	}
+	if report.Tasks[0].Ratio() != 1.5 || report.Tasks[1].Actual != time.Hour {
+		t.Errorf("per-task accuracy = %v, %s, want 1.5 and 1h", report.Tasks[0].Ratio(), report.Tasks[1].Actual)
+	}
+	if report.Unmeasured != 1 {
+		t.Errorf("Unmeasured = %d, want 1", report.Unmeasured)
+	}
+	if report.Estimated != 4*time.Hour || report.Actual != 4*time.Hour || report.Ratio() != 1 {
+		t.Errorf("time totals = %s, %s, %v, want 4h, 4h, 1", report.Estimated, report.Actual, report.Ratio())
+	}
+	if report.MeanError() != 0.5 {
+		t.Errorf("MeanError() = %v, want 0.5", report.MeanError())
+	}
+	if report.TimePerPoint() != 2*time.Hour {
+		t.Errorf("TimePerPoint() = %s, want 2h", report.TimePerPoint())
+	}
+}
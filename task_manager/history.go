@@ -0,0 +1,125 @@
+package task_manager
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TaskTrackerCLI/structures"
+)
+
+// HistoryFieldCreated - запись истории о появлении таска, NewValue содержит его имя
+const HistoryFieldCreated = "created"
+
+// HistoryFieldStatus - запись истории о смене статуса таска
+const HistoryFieldStatus = "status"
+
+// currentActor - имя пользователя ОС, от имени которого записываются изменения
+func currentActor() string {
+	for _, name := range []string{"USER", "USERNAME"} {
+		if actor := os.Getenv(name); actor != "" {
+			return actor
+		}
+	}
+	return "unknown"
+}
+
+// taskFieldChanges - изменения отслеживаемых полей между двумя состояниями таска. before == nil означает новый таск
+func taskFieldChanges(before *structures.Task, after structures.Task) []structures.HistoryEntry {
+	if before == nil {
+		return []structures.HistoryEntry{{Field: HistoryFieldCreated, NewValue: after.TaskName}}
+	}
+	fields := []struct {
+		name          string
+		before, after string
+	}{
+		{"name", before.TaskName, after.TaskName},
+		{"description", before.TaskDescription, after.TaskDescription},
+		{HistoryFieldStatus, before.TaskStatus, after.TaskStatus},
+		{"priority", before.TaskPriority, after.TaskPriority},
+		{"due", before.TaskDueDate, after.TaskDueDate},
+		{"recurrence", before.TaskRecurrence, after.TaskRecurrence},
+		{"estimate", before.TaskEstimate, after.TaskEstimate},
+		{"tags", strings.Join(before.TaskTags, ","), strings.Join(after.TaskTags, ",")},
+		{"blocked_by", idsString(before.TaskBlockedBy), idsString(after.TaskBlockedBy)},
+		{"project_id", idString(before.TaskProjectId), idString(after.TaskProjectId)},
+		{"parent_id", idString(before.TaskParentId), idString(after.TaskParentId)},
+		{"checklist", checklistString(*before), checklistString(after)},
+		{"comments", countString(len(before.TaskComments)), countString(len(after.TaskComments))},
+		{"time_spent", durationString(TimeSpent(*before)), durationString(TimeSpent(after))},
+		{"deleted_at", before.TaskDeletedAt, after.TaskDeletedAt},
+		{"archived_at", before.TaskArchivedAt, after.TaskArchivedAt},
+	}
+	var entries []structures.HistoryEntry
+	for _, field := range fields {
+		if field.before != field.after {
+			entries = append(entries, structures.HistoryEntry{Field: field.name, OldValue: field.before, NewValue: field.after})
+		}
+	}
+	return entries
+}
+
+func idString(id int) string {
+	if id == 0 {
+		return ""
+	}
+	return strconv.Itoa(id)
+}
+
+// checklistString - прогресс чек-листа вида 3/5, пусто если чек-листа нет
+func checklistString(task structures.Task) string {
+	done, total := ChecklistProgress(task)
+	if total == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%d/%d", done, total)
+}
+
+// countString - количество элементов, пусто если их нет
+func countString(count int) string {
+	if count == 0 {
+		return ""
+	}
+	return strconv.Itoa(count)
+}
+
+func durationString(duration time.Duration) string {
+	if duration == 0 {
+		return ""
+	}
+	return duration.String()
+}
+
+func idsString(ids []int) string {
+	values := make([]string, len(ids))
+	for i, id := range ids {
+		values[i] = strconv.Itoa(id)
+	}
+	return strings.Join(values, ",")
+}
+
+// stampHistory - дописывает в историю каждого измененного таска записи об изменении его полей
+func (store *recordingStore) stampHistory(kind, actor, at string) error {
+	for _, change := range store.changes {
+		if change.After == nil {
+			continue
+		}
+		entries := taskFieldChanges(change.Before, *change.After)
+		if len(entries) == 0 {
+			continue
+		}
+		task := change.After.Clone()
+		for _, entry := range entries {
+			entry.At = at
+			entry.Actor = actor
+			entry.Operation = kind
+			task.TaskHistory = append(task.TaskHistory, entry)
+		}
+		if err := store.Put(task); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package task_manager
+
+import (
+	"testing"
+
+	"github.com/TaskTrackerCLI/structures"
+)
+
+// TestTaskHistory - проверяет, что изменения полей и статуса попадают в историю таска, в том числе отмененные
+func TestTaskHistory(t *testing.T) {
+	tm, err := NewTaskManagerWithStore(NewMemoryStore())
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+	tm.Actor = "alice"
+
+	id, err := tm.AddTask("Draft", "first version")
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	if _, err := tm.UpdateTask(id, map[string]string{"task_name": "Release", "task_description": "first version"}); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if _, _, err := tm.MarkTaskAsInProgress(id); err != nil {
+		t.Fatalf("MarkTaskAsInProgress() error = %v", err)
+	}
+	tm.Actor = "bob"
+	if _, ok, err := tm.Undo(); !ok || err != nil {
+		t.Fatalf("Undo() = %v, %v", ok, err)
+	}
+	// повторная установка того же статуса ничего не меняет и в историю не попадает
+	if _, err := tm.MarkTaskAsTodo(id); err != nil {
+		t.Fatalf("MarkTaskAsTodo() error = %v", err)
+	}
+
+	task, _, err := tm.GetTask(id)
+	if err != nil {
+		t.Fatalf("GetTask() error = %v", err)
+	}
+	want := []structures.HistoryEntry{
+		{Actor: "alice", Operation: OperationAdd, Field: HistoryFieldCreated, NewValue: "Draft"},
+		{Actor: "alice", Operation: OperationUpdate, Field: "name", OldValue: "Draft", NewValue: "Release"},
+		{Actor: "alice", Operation: OperationStatus, Field: "status", OldValue: StatusTodo, NewValue: StatusInProgress},
+		{Actor: "bob", Operation: OperationUndo, Field: "status", OldValue: StatusInProgress, NewValue: StatusTodo},
+	}
+	if len(task.TaskHistory) != len(want) {
+		t.Fatalf("got %d history entries %+v, want %d", len(task.TaskHistory), task.TaskHistory, len(want))
+	}
+	for i, entry := range task.TaskHistory {
+		if entry.At == "" {
+			t.Errorf("entry %d has no timestamp", i)
+		}
+		entry.At = ""
+		if entry != want[i] {
+			t.Errorf("entry %d = %+v, want %+v", i, entry, want[i])
+		}
+	}
+}
+
+// TestFindTaskHistory - история таска из корзины и архива доступна через FindTask, хотя GetTask их не находит
+func TestFindTaskHistory(t *testing.T) {
+	tm, err := NewTaskManagerWithStore(NewMemoryStore())
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+	trashed, _ := tm.AddTask("old idea", "")
+	archived, _ := tm.AddTask("shipped", "")
+	if _, err := tm.DeleteTask(trashed); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+	if _, err := tm.MarkTaskAsDone(archived); err != nil {
+		t.Fatalf("MarkTaskAsDone() error = %v", err)
+	}
+	if _, err := tm.CleanDoneTasks(); err != nil {
+		t.Fatalf("CleanDoneTasks() error = %v", err)
+	}
+
+	for _, id := range []int{trashed, archived} {
+		if _, ok, _ := tm.GetTask(id); ok {
+			t.Errorf("GetTask(%d) found a task outside the working set", id)
+		}
+		task, ok, err := tm.FindTask(id)
+		if !ok || err != nil {
+			t.Fatalf("FindTask(%d) = %v, %v", id, ok, err)
+		}
+		if len(task.TaskHistory) < 2 {
+			t.Errorf("FindTask(%d) history = %+v, want creation and removal", id, task.TaskHistory)
+		}
+	}
+	if _, ok, _ := tm.FindTask(42); ok {
+		t.Errorf("FindTask() on a missing task ok = true")
+	}
+}
@@ -1,16 +1,64 @@
 package task_manager
 
-import "github.com/TaskTrackerCLI/structures"
+import (
+	"time"
+
+	"github.com/TaskTrackerCLI/dates"
+	"github.com/TaskTrackerCLI/structures"
+)
+
+var _ TaskManagerInterface = (*TaskManager)(nil)
 
 type TaskManagerInterface interface {
-	AddTask(name, description string) int
-	UpdateTask(id int, values map[string]string) bool
-	DeleteTask(id int) int
-	MarkTaskAsDone(id int) bool
-	MarkTaskAsInProgress(id int) bool
-	MarkTaskAsTodo(id int) bool
-	ListAllTasks() []structures.Task
-	ListDoneTasks() []structures.Task
-	ListInProgressTasks() []structures.Task
-	ListTodoTasks() []structures.Task
+	AddTask(name, description string) (int, error)
+	AddTaskWithValues(name, description string, values map[string]string) (int, error)
+	GetTask(id int) (structures.Task, bool, error)
+	FindTask(id int) (structures.Task, bool, error)
+	UpdateTask(id int, values map[string]string) (bool, error)
+	DeleteTask(id int) (bool, error)
+	SetStatus(id int, status string) (bool, StatusChange, error)
+	MarkTaskAsDone(id int) (bool, error)
+	MarkTaskAsInProgress(id int) (bool, []int, error)
+	MarkTaskAsTodo(id int) (bool, error)
+	ListAllTasks() ([]structures.Task, error)
+	ListTasksByStatus(status string) ([]structures.Task, error)
+	ListDoneTasks() ([]structures.Task, error)
+	ListInProgressTasks() ([]structures.Task, error)
+	ListTodoTasks() ([]structures.Task, error)
+	SearchTasks(query string) ([]structures.Task, error)
+	Agenda(now time.Time) (map[dates.Bucket][]structures.Task, error)
+	AddTag(id int, tag string) (bool, error)
+	RemoveTag(id int, tag string) (bool, error)
+	TagCounts() ([]TagCount, error)
+	CreateProject(name string) (structures.Project, error)
+	FindProject(name string) (structures.Project, bool, error)
+	RenameProject(name, newName string) (bool, error)
+	DeleteProject(name, moveTo string, deleteTasks bool) (bool, error)
+	ListProjects() ([]ProjectProgress, error)
+	MarkTaskTreeAsDone(id int) (bool, int, error)
+	TaskTree() ([]TreeNode, error)
+	AddDependency(id, blockerId int) (bool, error)
+	RemoveDependency(id, blockerId int) (bool, error)
+	Blockers() (map[int][]int, error)
+	ListReadyTasks() ([]structures.Task, error)
+	StartTimer(id int) (bool, []int, error)
+	StopTimer() (Timer, bool, error)
+	ActiveTimer() (Timer, bool, error)
+	LogWork(id int, duration time.Duration, end time.Time) (bool, error)
+	Timesheet(from, to time.Time) (Timesheet, error)
+	EstimateReport() (EstimateReport, error)
+	AddComment(id int, text string) (bool, error)
+	Comments(id int) ([]structures.Comment, bool, error)
+	AddCheckItem(id int, text string) (bool, error)
+	ToggleCheckItem(id, n int, autoDone bool) (bool, AutoDone, error)
+	RemoveCheckItem(id, n int, autoDone bool) (bool, AutoDone, error)
+	CleanDoneTasks() (int, error)
+	ListTrash() ([]structures.Task, error)
+	RestoreTask(id int) (bool, error)
+	EmptyTrash(olderThan time.Duration) (int, error)
+	ListArchive() ([]structures.Task, error)
+	SearchArchive(query string) ([]structures.Task, error)
+	UnarchiveTask(id int) (bool, error)
+	Undo() (Operation, bool, error)
+	Redo() (Operation, bool, error)
 }
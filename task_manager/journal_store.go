@@ -0,0 +1,339 @@
+package task_manager
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/TaskTrackerCLI/structures"
+)
+
+// DefaultCompactEvery - после скольких событий в журнале JournalStore по умолчанию пишет новый снимок
+const DefaultCompactEvery = 100
+
+// journalEvent - одна строка журнала: операция и итоговое состояние затронутых тасков
+type journalEvent struct {
+	// SchemaVersion - версия схемы тасков в Changes, события без нее записаны до версии 3
+	SchemaVersion int             `json:"schema_version,omitempty"`
+	Seq           int64           `json:"seq"`
+	Kind          string          `json:"kind"`
+	At            string          `json:"at"`
+	NextId        int             `json:"next_id"`
+	Changes       []journalChange `json:"changes"`
+	// Meta - новые служебные значения, null удаляет ключ
+	Meta map[string]json.RawMessage `json:"meta,omitempty"`
+}
+
+// journalChange - новое состояние таска, Task == nil означает удаление
+type journalChange struct {
+	TaskId int              `json:"task_id"`
+	Task   *structures.Task `json:"task"`
+}
+
+// JournalStore - хранилище, которое не переписывает файл тасков на каждое изменение, а дописывает
+// события в журнал JSON Lines (FilePath + ".journal"). Текущее состояние - это снимок в формате
+// JSONFileStore плюс события журнала после него. Когда событий набирается CompactEvery, пишется
+// новый снимок, а журнал очищается
+type JournalStore struct {
+	FilePath     string
+	CompactEvery int
+
+	snapshot *JSONFileStore
+	pending  *journalEvent
+	events   int
+	// validSize - длина журнала до оборванной последней строки, если она есть
+	validSize int64
+}
+
+func NewJournalStore(filePath string) *JournalStore {
+	snapshot := NewJSONFileStore(filePath)
+	snapshot.journaled = true
+	return &JournalStore{
+		FilePath:     filePath,
+		CompactEvery: DefaultCompactEvery,
+		snapshot:     snapshot,
+	}
+}
+
+func (store *JournalStore) journalPath() string {
+	return journalPath(store.FilePath)
+}
+
+func journalPath(filePath string) string {
+	return filePath + ".journal"
+}
+
+// Load - читает снимок и проигрывает поверх него события журнала.
+// Оборванная последняя строка (сбой во время дозаписи) пропускается с предупреждением
+func (store *JournalStore) Load() error {
+	store.pending = nil
+	store.events = 0
+	store.validSize = 0
+	if err := store.snapshot.Load(); err != nil {
+		return err
+	}
+
+	content, err := os.ReadFile(store.journalPath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	for line := 1; len(content) > 0; line++ {
+		end := bytes.IndexByte(content, '\n')
+		if end < 0 {
+			log.Printf("warning: ignoring incomplete last event in %s", store.journalPath())
+			break
+		}
+		var event journalEvent
+		if err := json.Unmarshal(content[:end], &event); err != nil {
+			return fmt.Errorf("failed to read journal event on line %d: %w", line, err)
+		}
+		content = content[end+1:]
+		store.validSize += int64(end + 1)
+
+		// события до снимка уже в нем учтены: сбой мог случиться между записью снимка и очисткой журнала
+		if event.Seq <= store.snapshot.file.JournalSeq {
+			continue
+		}
+		if err := migrateEvent(&event); err != nil {
+			return fmt.Errorf("journal event on line %d: %w", line, err)
+		}
+		if err := store.apply(event); err != nil {
+			return fmt.Errorf("failed to apply journal event on line %d: %w", line, err)
+		}
+		store.events++
+	}
+	return nil
+}
+
+// migrateEvent - доводит таски события до текущей схемы так же, как миграции файла доводят снимок
+func migrateEvent(event *journalEvent) error {
+	version := event.SchemaVersion
+	if version == 0 {
+		// журнал появился при схеме 2, события без версии записаны ею
+		version = 2
+	}
+	if version > CurrentSchemaVersion {
+		return fmt.Errorf("schema version %d, this TaskTracker supports up to %d", version, CurrentSchemaVersion)
+	}
+	for _, change := range event.Changes {
+		if change.Task != nil {
+			migrateTask(change.Task, version)
+		}
+	}
+	return nil
+}
+
+// apply - применяет событие к снимку. Таски идут через Put и Delete снимка, чтобы архивные попали в архив
+func (store *JournalStore) apply(event journalEvent) error {
+	for _, change := range event.Changes {
+		var err error
+		if change.Task == nil {
+			err = store.snapshot.Delete(change.TaskId)
+		} else {
+			err = store.snapshot.Put(*change.Task)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	for key, value := range event.Meta {
+		if isJSONNull(value) {
+			delete(store.snapshot.file.Meta, key)
+		} else {
+			store.snapshot.file.Meta[key] = value
+		}
+	}
+	if event.NextId > store.snapshot.file.NextId {
+		store.snapshot.file.NextId = event.NextId
+	}
+	store.snapshot.file.JournalSeq = event.Seq
+	return nil
+}
+
+// Record - задает вид операции и время для события, которое запишет следующий Save
+func (store *JournalStore) Record(op Operation) error {
+	event := store.pendingEvent()
+	event.Kind = op.Kind
+	event.At = op.At
+	return nil
+}
+
+func (store *JournalStore) pendingEvent() *journalEvent {
+	if store.pending == nil {
+		store.pending = &journalEvent{Kind: "put", At: time.Now().Format(time.RFC3339)}
+	}
+	return store.pending
+}
+
+func (store *JournalStore) addChange(id int, task *structures.Task) {
+	event := store.pendingEvent()
+	for i := range event.Changes {
+		if event.Changes[i].TaskId == id {
+			event.Changes[i].Task = task
+			return
+		}
+	}
+	event.Changes = append(event.Changes, journalChange{TaskId: id, Task: task})
+}
+
+// Save - дописывает накопленные изменения одним событием в журнал и делает fsync
+func (store *JournalStore) Save() error {
+	if store.pending == nil {
+		return nil
+	}
+	event := *store.pending
+	event.SchemaVersion = CurrentSchemaVersion
+	event.Seq = store.snapshot.file.JournalSeq + 1
+	event.NextId = store.snapshot.file.NextId
+	line, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to marshal journal event: %w", err)
+	}
+
+	file, err := os.OpenFile(store.journalPath(), os.O_CREATE|os.O_RDWR|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open journal: %w", err)
+	}
+	if err := store.dropIncompleteTail(file); err != nil {
+		_ = file.Close()
+		return err
+	}
+	writer := bufio.NewWriter(file)
+	_, err = writer.Write(append(line, '\n'))
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to append to journal: %w", err)
+	}
+
+	store.snapshot.file.JournalSeq = event.Seq
+	store.pending = nil
+	store.events++
+	store.validSize += int64(len(line) + 1)
+	if store.CompactEvery > 0 && store.events >= store.CompactEvery {
+		return store.Compact()
+	}
+	return nil
+}
+
+// dropIncompleteTail - обрезает оборванную строку, оставшуюся от упавшей дозаписи, чтобы новое
+// событие не склеилось с ней. Полные строки после validSize означают, что журнал изменил другой
+// процесс, и писать поверх них нельзя
+func (store *JournalStore) dropIncompleteTail(file *os.File) error {
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat journal: %w", err)
+	}
+	if info.Size() == store.validSize {
+		return nil
+	}
+	if info.Size() > store.validSize {
+		tail := make([]byte, info.Size()-store.validSize)
+		if _, err := file.ReadAt(tail, store.validSize); err != nil {
+			return fmt.Errorf("failed to read journal: %w", err)
+		}
+		if bytes.IndexByte(tail, '\n') < 0 {
+			if err := file.Truncate(store.validSize); err != nil {
+				return fmt.Errorf("failed to truncate incomplete journal event: %w", err)
+			}
+			return nil
+		}
+	}
+	return fmt.Errorf("journal %s was modified by another process, reload and retry", store.journalPath())
+}
+
+// Compact - записывает текущее состояние снимком и очищает журнал
+func (store *JournalStore) Compact() error {
+	if err := store.snapshot.Save(); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(store.journalPath(), nil, 0644); err != nil {
+		return fmt.Errorf("failed to truncate journal: %w", err)
+	}
+	store.events = 0
+	store.validSize = 0
+	return nil
+}
+
+// Lock - блокирует снимок и журнал от изменений другими процессами
+func (store *JournalStore) Lock(timeout time.Duration) (func() error, error) {
+	return lockFile(store.FilePath+".lock", timeout)
+}
+
+func (store *JournalStore) Get(id int) (structures.Task, bool, error) {
+	return store.snapshot.Get(id)
+}
+
+func (store *JournalStore) Put(task structures.Task) error {
+	if err := store.snapshot.Put(task); err != nil {
+		return err
+	}
+	store.addChange(task.TaskId, &task)
+	return nil
+}
+
+func (store *JournalStore) Delete(id int) error {
+	if err := store.snapshot.Delete(id); err != nil {
+		return err
+	}
+	store.addChange(id, nil)
+	return nil
+}
+
+func (store *JournalStore) List() ([]structures.Task, error) {
+	return store.snapshot.List()
+}
+
+func (store *JournalStore) ListActive() ([]structures.Task, error) {
+	return store.snapshot.ListActive()
+}
+
+func (store *JournalStore) ListUnarchived() ([]structures.Task, error) {
+	return store.snapshot.ListUnarchived()
+}
+
+func (store *JournalStore) ListArchivedSince(since time.Time) ([]structures.Task, error) {
+	return store.snapshot.ListArchivedSince(since)
+}
+
+func (store *JournalStore) NextID() (int, error) {
+	return store.snapshot.NextID()
+}
+
+func (store *JournalStore) Meta(key string) (json.RawMessage, bool, error) {
+	return store.snapshot.Meta(key)
+}
+
+func (store *JournalStore) SetMeta(key string, value json.RawMessage) error {
+	if err := store.snapshot.SetMeta(key, value); err != nil {
+		return err
+	}
+	event := store.pendingEvent()
+	if event.Meta == nil {
+		event.Meta = make(map[string]json.RawMessage)
+	}
+	if value == nil {
+		value = json.RawMessage("null")
+	}
+	event.Meta[key] = value
+	return nil
+}
+
+func isJSONNull(value json.RawMessage) bool {
+	return len(value) == 0 || string(value) == "null"
+}
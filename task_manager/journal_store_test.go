@@ -0,0 +1,263 @@
+package task_manager
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func readJournal(t *testing.T, path string) []journalEvent {
+	t.Helper()
+	content, err := os.ReadFile(journalPath(path))
+	if err != nil && !os.IsNotExist(err) {
+		t.Fatalf("Failed to read journal: %v", err)
+	}
+	var events []journalEvent
+	for _, line := range bytes.Split(bytes.TrimSpace(content), []byte("\n")) {
+		if len(line) == 0 {
+			continue
+		}
+		var event journalEvent
+		if err := json.Unmarshal(line, &event); err != nil {
+			t.Fatalf("Invalid journal line %q: %v", line, err)
+		}
+		events = append(events, event)
+	}
+	return events
+}
+
+// TestJournalStoreRecordsOperations - каждая операция TaskManager дописывает одно событие, а состояние
+// восстанавливается проигрыванием журнала.
+func TestJournalStoreRecordsOperations(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	tm, err := NewTaskManagerWithStore(NewJournalStore(path))
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+
+	for _, name := range []string{"first", "second", "third"} {
+		if _, err := tm.AddTask(name, ""); err != nil {
+			t.Fatalf("AddTask() error = %v", err)
+		}
+	}
+	if _, err := tm.UpdateTask(1, map[string]string{"task_name": "renamed"}); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if _, err := tm.MarkTaskAsDone(2); err != nil {
+		t.Fatalf("MarkTaskAsDone() error = %v", err)
+	}
+	if _, err := tm.MarkTaskAsDone(3); err != nil {
+		t.Fatalf("MarkTaskAsDone() error = %v", err)
+	}
+	if _, err := tm.CleanDoneTasks(); err != nil {
+		t.Fatalf("CleanDoneTasks() error = %v", err)
+	}
+	if _, err := tm.DeleteTask(999); err != nil {
+		t.Fatalf("DeleteTask() error = %v", err)
+	}
+
+	events := readJournal(t, path)
+	wantKinds := []string{OperationAdd, OperationAdd, OperationAdd, OperationUpdate, OperationStatus, OperationStatus, OperationClean}
+	if len(events) != len(wantKinds) {
+		t.Fatalf("journal has %d events, want %d", len(events), len(wantKinds))
+	}
+	for i, event := range events {
+		if event.Kind != wantKinds[i] {
+			t.Errorf("event %d kind = %s, want %s", i, event.Kind, wantKinds[i])
+		}
+		if event.Seq != int64(i+1) {
+			t.Errorf("event %d seq = %d, want %d", i, event.Seq, i+1)
+		}
+	}
+	if clean := events[len(events)-1]; len(clean.Changes) != 2 {
+		t.Errorf("clean event has %d changes, want 2 in a single event", len(clean.Changes))
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("snapshot must not be written before compaction, stat error = %v", err)
+	}
+
+	replayed, err := NewTaskManagerWithStore(NewJournalStore(path))
+	if err != nil {
+		t.Fatalf("Failed to replay journal: %v", err)
+	}
+	tasks, err := replayed.ListAllTasks()
+	if err != nil {
+		t.Fatalf("ListAllTasks() error = %v", err)
+	}
+	if len(tasks) != 1 || tasks[0].TaskName != "renamed" {
+		t.Errorf("replayed state = %+v, want only the renamed task 1", tasks)
+	}
+	if id, err := replayed.AddTask("fourth", ""); err != nil || id != 4 {
+		t.Errorf("AddTask() after replay = %d, %v, want id 4", id, err)
+	}
+
+	if _, err := NewTaskManager(path); err == nil || !strings.Contains(err.Error(), "journal") {
+		t.Errorf("NewTaskManager() with pending journal error = %v, want refusal", err)
+	}
+}
+
+// TestJournalStoreCompaction - после CompactEvery событий состояние переносится в снимок, журнал очищается.
+func TestJournalStoreCompaction(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	store := NewJournalStore(path)
+	store.CompactEvery = 3
+	tm, err := NewTaskManagerWithStore(store)
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+
+	for i := 0; i < 4; i++ {
+		if _, err := tm.AddTask("task", ""); err != nil {
+			t.Fatalf("AddTask() error = %v", err)
+		}
+	}
+
+	if events := readJournal(t, path); len(events) != 1 || events[0].Seq != 4 {
+		t.Errorf("journal after compaction = %+v, want only event 4", events)
+	}
+	snapshot := NewJSONFileStore(path)
+	snapshot.journaled = true
+	if err := snapshot.Load(); err != nil {
+		t.Fatalf("Failed to load snapshot: %v", err)
+	}
+	if tasks, _ := snapshot.List(); len(tasks) != 3 || snapshot.file.JournalSeq != 3 {
+		t.Errorf("snapshot has %d tasks at seq %d, want 3 tasks at seq 3", len(tasks), snapshot.file.JournalSeq)
+	}
+
+	reloaded, err := NewTaskManagerWithStore(NewJournalStore(path))
+	if err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	if tasks, _ := reloaded.ListAllTasks(); len(tasks) != 4 {
+		t.Errorf("reloaded %d tasks, want 4", len(tasks))
+	}
+}
+
+// TestJournalStoreIncompleteTail - оборванная последняя строка игнорируется и не портит следующую запись.
+func TestJournalStoreIncompleteTail(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	tm, err := NewTaskManagerWithStore(NewJournalStore(path))
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+	if _, err := tm.AddTask("kept", ""); err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+
+	file, err := os.OpenFile(journalPath(path), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open journal: %v", err)
+	}
+	if _, err := file.WriteString(`{"seq":2,"kind":"add","chan`); err != nil {
+		t.Fatalf("Failed to write torn event: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatalf("Failed to close journal: %v", err)
+	}
+
+	if _, err := tm.AddTask("after crash", ""); err != nil {
+		t.Fatalf("AddTask() after torn write error = %v", err)
+	}
+	if events := readJournal(t, path); len(events) != 2 {
+		t.Errorf("journal has %d valid events, want 2", len(events))
+	}
+
+	reloaded, err := NewTaskManagerWithStore(NewJournalStore(path))
+	if err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	if tasks, _ := reloaded.ListAllTasks(); len(tasks) != 2 {
+		t.Errorf("reloaded %d tasks, want 2", len(tasks))
+	}
+}
+
+// TestJournalStoreMigratesOldEvents - события без версии схемы записаны до приоритетов и при проигрывании
+// получают те же значения по умолчанию, что и таски из мигрированного файла, а события новее программы
+// не читаются.
+func TestJournalStoreMigratesOldEvents(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	tm, err := NewTaskManagerWithStore(NewJournalStore(path))
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+	if _, err := tm.AddTask("current", ""); err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	if events := readJournal(t, path); len(events) != 1 || events[0].SchemaVersion != CurrentSchemaVersion {
+		t.Fatalf("journal = %+v, want one event with schema version %d", events, CurrentSchemaVersion)
+	}
+
+	file, err := os.OpenFile(journalPath(path), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open journal: %v", err)
+	}
+	legacy := `{"seq":2,"kind":"add","next_id":3,"changes":[{"task_id":2,"task":{"task_id":2,"task_name":"legacy","task_status":"TODO"}}]}` + "\n"
+	if _, err := file.WriteString(legacy); err != nil {
+		t.Fatalf("Failed to write legacy event: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatalf("Failed to close journal: %v", err)
+	}
+
+	reloaded, err := NewTaskManagerWithStore(NewJournalStore(path))
+	if err != nil {
+		t.Fatalf("Failed to reload: %v", err)
+	}
+	task, ok, err := reloaded.GetTask(2)
+	if err != nil || !ok {
+		t.Fatalf("GetTask(2) = %v, %v, want the legacy task", ok, err)
+	}
+	if task.TaskPriority != DefaultPriority {
+		t.Errorf("legacy task priority = %q, want %q", task.TaskPriority, DefaultPriority)
+	}
+
+	file, err = os.OpenFile(journalPath(path), os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		t.Fatalf("Failed to open journal: %v", err)
+	}
+	if _, err := file.WriteString(`{"schema_version":99,"seq":3,"kind":"add","changes":[]}` + "\n"); err != nil {
+		t.Fatalf("Failed to write future event: %v", err)
+	}
+	if err := file.Close(); err != nil {
+		t.Fatalf("Failed to close journal: %v", err)
+	}
+	if _, err := NewTaskManagerWithStore(NewJournalStore(path)); err == nil || !strings.Contains(err.Error(), "schema version 99") {
+		t.Errorf("loading a journal from a newer version error = %v, want a schema version error", err)
+	}
+}
+
+// TestJournalStoreEventSizeDoesNotGrowWithUndo - событие журнала несет только новую операцию истории
+// отмены, а не всю историю, поэтому его размер не зависит от глубины истории.
+func TestJournalStoreEventSizeDoesNotGrowWithUndo(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	store := NewJournalStore(path)
+	store.CompactEvery = 0
+	tm, err := NewTaskManagerWithStore(store)
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+	for i := 0; i < 2*DefaultHistoryLimit; i++ {
+		if _, err := tm.AddTask("task", "same size"); err != nil {
+			t.Fatalf("AddTask() error = %v", err)
+		}
+	}
+
+	content, err := os.ReadFile(journalPath(path))
+	if err != nil {
+		t.Fatalf("Failed to read journal: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(content)), "\n")
+	first, last := len(lines[1]), len(lines[len(lines)-1])
+	// стеки с номерами операций немного растут до HistoryLimit, сами операции - нет
+	if last > first+DefaultHistoryLimit*4 {
+		t.Errorf("last event is %d bytes, second is %d: event size grows with undo history", last, first)
+	}
+	for i, event := range readJournal(t, path) {
+		if len(event.Meta) > 3 {
+			t.Errorf("event %d writes %d meta values, want the new undo entry, the index and at most one evicted entry", i+1, len(event.Meta))
+		}
+	}
+}
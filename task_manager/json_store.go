@@ -0,0 +1,380 @@
+package task_manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/TaskTrackerCLI/structures"
+)
+
+// JSONFileStore - хранилище тасков в json файле с версией схемы (см. taskFile).
+// Рядом с файлом хранится копия предыдущей удачной версии с суффиксом .bak.
+// Таски из архива лежат в отдельном файле с суффиксом .archive (см. archiveFile), он читается
+// только когда нужен архивный таск, поэтому рабочий набор не растет вместе с архивом
+type JSONFileStore struct {
+	FilePath string
+	file     taskFile
+	// journaled - файл является снимком JournalStore и журнал рядом с ним ожидаем
+	journaled bool
+
+	// archived - id тасков в архиве и время, когда их туда убрали. Сохраняется в основном файле
+	archived map[int]string
+	// archive - таски из файла архива, nil пока он не прочитан
+	archive map[int]structures.Task
+	// unarchived - таски, убранные из архива после последнего Save. До записи основного файла
+	// они остаются и в файле архива, чтобы сбой между двумя записями их не потерял
+	unarchived   map[int]structures.Task
+	archiveDirty bool
+}
+
+func NewJSONFileStore(filePath string) *JSONFileStore {
+	return &JSONFileStore{
+		FilePath: filePath,
+		file:     newTaskFile(),
+		archived: make(map[int]string),
+	}
+}
+
+// Load - читает таски из json файла, отсутствующий или пустой файл считается пустым хранилищем.
+// Файлы старых версий схемы мигрируют в памяти и сохраняются в новом формате при следующем Save.
+// Если файл поврежден, таски загружаются из резервной копии
+func (store *JSONFileStore) Load() error {
+	if !store.journaled {
+		if info, err := os.Stat(journalPath(store.FilePath)); err == nil && info.Size() > 0 {
+			return fmt.Errorf("%s has unapplied changes in %s, open it with the journal storage", store.FilePath, journalPath(store.FilePath))
+		}
+	}
+
+	store.archived = make(map[int]string)
+	store.archive = nil
+	store.unarchived = nil
+	store.archiveDirty = false
+
+	fileContent, err := os.ReadFile(store.FilePath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			store.file = newTaskFile()
+			return nil
+		}
+		return fmt.Errorf("failed to read tasks: %w", err)
+	}
+
+	file, err := decodeTaskFile(fileContent)
+	if err != nil {
+		backupContent, backupErr := os.ReadFile(store.backupPath())
+		if backupErr != nil {
+			return err
+		}
+		backupFile, backupErr := decodeTaskFile(backupContent)
+		if backupErr != nil {
+			return err
+		}
+		log.Printf("warning: %s is corrupted (%v), loaded previous version from %s", store.FilePath, err, store.backupPath())
+		file = backupFile
+	}
+	store.file = file
+	for id, archivedAt := range file.Archived {
+		store.archived[id] = archivedAt
+	}
+	// до версии 4 архивные таски хранились в основном файле, они переезжают в архив при следующем Save
+	for _, task := range file.Tasks {
+		if isArchived(task) {
+			if err := store.Put(task); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Save - атомарно записывает все таски в json файл, предыдущая версия сохраняется в .bak.
+// Измененный архив записывается раньше основного файла: пока основной файл не записан, в нем
+// действует старый список архивных тасков, а лишние записи в архиве игнорируются
+func (store *JSONFileStore) Save() error {
+	if store.archiveDirty {
+		tasks := make(map[int]structures.Task, len(store.archive)+len(store.unarchived))
+		for id, task := range store.unarchived {
+			tasks[id] = task
+		}
+		for id, task := range store.archive {
+			tasks[id] = task
+		}
+		content, err := json.Marshal(archiveFile{SchemaVersion: CurrentSchemaVersion, Tasks: tasks})
+		if err != nil {
+			return fmt.Errorf("failed to write archive file: %w", err)
+		}
+		if err := writeWithBackup(store.archivePath(), content); err != nil {
+			return fmt.Errorf("failed to write archive file: %w", err)
+		}
+	}
+
+	store.file.SchemaVersion = CurrentSchemaVersion
+	store.file.Archived = make(map[int]string, len(store.archived))
+	for id, archivedAt := range store.archived {
+		store.file.Archived[id] = archivedAt
+	}
+	tasks, err := json.Marshal(store.file)
+	if err != nil {
+		return fmt.Errorf("failed to write tasks file: %w", err)
+	}
+	if err := writeWithBackup(store.FilePath, tasks); err != nil {
+		return fmt.Errorf("failed to write tasks file: %w", err)
+	}
+	store.unarchived = nil
+	store.archiveDirty = false
+	return nil
+}
+
+// writeWithBackup - атомарно записывает файл, сохранив его предыдущую версию в .bak
+func writeWithBackup(path string, content []byte) error {
+	previous, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if len(previous) != 0 && json.Valid(previous) {
+		if err := writeFileAtomic(path+".bak", previous, 0644); err != nil {
+			return fmt.Errorf("failed to write backup: %w", err)
+		}
+	}
+	return writeFileAtomic(path, content, 0644)
+}
+
+func (store *JSONFileStore) archivePath() string {
+	return store.FilePath + ".archive"
+}
+
+// loadArchive - читает файл архива при первом обращении к архивному таску.
+// Из файла берутся только таски из списка архивных в основном файле
+func (store *JSONFileStore) loadArchive() error {
+	if store.archive != nil {
+		return nil
+	}
+	file, err := readArchiveFile(store.archivePath())
+	if err != nil {
+		backup, backupErr := readArchiveFile(store.archivePath() + ".bak")
+		if backupErr != nil {
+			return err
+		}
+		log.Printf("warning: %s is corrupted (%v), loaded previous version from %s.bak", store.archivePath(), err, store.archivePath())
+		file = backup
+	}
+	store.archive = make(map[int]structures.Task, len(store.archived))
+	for id := range store.archived {
+		task, ok := file.Tasks[id]
+		if !ok {
+			return fmt.Errorf("archived task %d is missing from %s", id, store.archivePath())
+		}
+		store.archive[id] = task
+	}
+	return nil
+}
+
+func readArchiveFile(path string) (archiveFile, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return archiveFile{}, nil
+		}
+		return archiveFile{}, fmt.Errorf("failed to read archive: %w", err)
+	}
+	var file archiveFile
+	if err := json.Unmarshal(content, &file); err != nil {
+		return archiveFile{}, fmt.Errorf("failed to unmarshal archive: %w", err)
+	}
+	if file.SchemaVersion > CurrentSchemaVersion {
+		return archiveFile{}, fmt.Errorf("archive has schema version %d, this TaskTracker supports up to %d", file.SchemaVersion, CurrentSchemaVersion)
+	}
+	return file, nil
+}
+
+// Lock - блокирует файл тасков от изменений другими процессами через файл .lock
+func (store *JSONFileStore) Lock(timeout time.Duration) (func() error, error) {
+	return lockFile(store.FilePath+".lock", timeout)
+}
+
+func (store *JSONFileStore) backupPath() string {
+	return store.FilePath + ".bak"
+}
+
+// writeFileAtomic - пишет данные во временный файл рядом с path, делает fsync и переименовывает его в path.
+// Файл path в любой момент содержит либо старую, либо новую версию целиком
+func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	dir := filepath.Dir(path)
+	tmpFile, err := os.CreateTemp(dir, filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmpFile.Name()
+	defer func() {
+		if tmpPath != "" {
+			_ = os.Remove(tmpPath)
+		}
+	}()
+
+	if _, err := tmpFile.Write(data); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Sync(); err != nil {
+		_ = tmpFile.Close()
+		return err
+	}
+	if err := tmpFile.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, perm); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	tmpPath = ""
+
+	// fsync каталога, чтобы переименование пережило сбой питания. Не на всех ОС каталог можно синхронизировать
+	if dirFile, err := os.Open(dir); err == nil {
+		_ = dirFile.Sync()
+		_ = dirFile.Close()
+	}
+	return nil
+}
+
+func (store *JSONFileStore) Get(id int) (structures.Task, bool, error) {
+	if _, ok := store.archived[id]; ok {
+		if err := store.loadArchive(); err != nil {
+			return structures.Task{}, false, err
+		}
+		return store.archive[id].Clone(), true, nil
+	}
+	task, ok := store.file.Tasks[id]
+	return task.Clone(), ok, nil
+}
+
+// Put - сохраняет таск и сдвигает next_id за его id, next_id никогда не уменьшается.
+// Архивный таск уходит в файл архива, таск, вернувшийся из архива, - в основной файл
+func (store *JSONFileStore) Put(task structures.Task) error {
+	if _, ok := store.archived[task.TaskId]; ok || isArchived(task) {
+		if err := store.loadArchive(); err != nil {
+			return err
+		}
+		store.removeFromArchive(task.TaskId)
+	}
+	if isArchived(task) {
+		delete(store.file.Tasks, task.TaskId)
+		store.archive[task.TaskId] = task.Clone()
+		store.archived[task.TaskId] = task.TaskArchivedAt
+		store.archiveDirty = true
+	} else {
+		store.file.Tasks[task.TaskId] = task.Clone()
+	}
+	if task.TaskId >= store.file.NextId {
+		store.file.NextId = task.TaskId + 1
+	}
+	return nil
+}
+
+func (store *JSONFileStore) Delete(id int) error {
+	if _, ok := store.archived[id]; ok {
+		if err := store.loadArchive(); err != nil {
+			return err
+		}
+		store.removeFromArchive(id)
+	}
+	delete(store.file.Tasks, id)
+	return nil
+}
+
+// removeFromArchive - убирает таск из загруженного архива, запомнив его для следующего Save
+func (store *JSONFileStore) removeFromArchive(id int) {
+	task, ok := store.archive[id]
+	if !ok {
+		return
+	}
+	if store.unarchived == nil {
+		store.unarchived = make(map[int]structures.Task)
+	}
+	store.unarchived[id] = task
+	delete(store.archive, id)
+	delete(store.archived, id)
+	store.archiveDirty = true
+}
+
+// List - все таски вместе с архивом, поэтому читает файл архива
+func (store *JSONFileStore) List() ([]structures.Task, error) {
+	if err := store.loadArchive(); err != nil {
+		return nil, err
+	}
+	tasks := make([]structures.Task, 0, len(store.file.Tasks)+len(store.archive))
+	for _, task := range store.file.Tasks {
+		tasks = append(tasks, task.Clone())
+	}
+	for _, task := range store.archive {
+		tasks = append(tasks, task.Clone())
+	}
+	return tasks, nil
+}
+
+// ListActive - таски рабочего набора из основного файла, файл архива не читается
+func (store *JSONFileStore) ListActive() ([]structures.Task, error) {
+	tasks := make([]structures.Task, 0, len(store.file.Tasks))
+	for _, task := range store.file.Tasks {
+		if isActive(task) {
+			tasks = append(tasks, task.Clone())
+		}
+	}
+	return tasks, nil
+}
+
+// ListUnarchived - таски из основного файла: рабочий набор и корзина, файл архива не читается
+func (store *JSONFileStore) ListUnarchived() ([]structures.Task, error) {
+	tasks := make([]structures.Task, 0, len(store.file.Tasks))
+	for _, task := range store.file.Tasks {
+		tasks = append(tasks, task.Clone())
+	}
+	return tasks, nil
+}
+
+// ListArchivedSince - архивные таски, убранные в архив не раньше since. Файл архива читается,
+// только если такие таски есть
+func (store *JSONFileStore) ListArchivedSince(since time.Time) ([]structures.Task, error) {
+	var ids []int
+	for id, archivedAt := range store.archived {
+		if archivedSince(archivedAt, since) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	if err := store.loadArchive(); err != nil {
+		return nil, err
+	}
+	tasks := make([]structures.Task, 0, len(ids))
+	for _, id := range ids {
+		tasks = append(tasks, store.archive[id].Clone())
+	}
+	return tasks, nil
+}
+
+func (store *JSONFileStore) NextID() (int, error) {
+	id := store.file.NextId
+	store.file.NextId++
+	return id, nil
+}
+
+func (store *JSONFileStore) Meta(key string) (json.RawMessage, bool, error) {
+	value, ok := store.file.Meta[key]
+	return value, ok, nil
+}
+
+func (store *JSONFileStore) SetMeta(key string, value json.RawMessage) error {
+	if value == nil {
+		delete(store.file.Meta, key)
+		return nil
+	}
+	store.file.Meta[key] = value
+	return nil
+}
@@ -0,0 +1,20 @@
+package task_manager
+
+import (
+	"errors"
+	"time"
+)
+
+// DefaultLockTimeout - сколько TaskManager по умолчанию ждет блокировку хранилища
+const DefaultLockTimeout = 5 * time.Second
+
+const lockRetryInterval = 10 * time.Millisecond
+
+// ErrLockTimeout - блокировку хранилища не удалось получить за отведенное время
+var ErrLockTimeout = errors.New("timed out waiting for tasks lock")
+
+// Locker - хранилище, которое умеет блокировать себя от изменений другими процессами
+type Locker interface {
+	// Lock - ждет эксклюзивную блокировку не дольше timeout и возвращает функцию для ее снятия
+	Lock(timeout time.Duration) (unlock func() error, err error)
+}
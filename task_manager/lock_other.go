@@ -0,0 +1,10 @@
+//go:build !unix
+
+package task_manager
+
+import "time"
+
+// lockFile - на платформах без flock межпроцессная блокировка не поддерживается
+func lockFile(path string, timeout time.Duration) (func() error, error) {
+	return func() error { return nil }, nil
+}
@@ -0,0 +1,86 @@
+//go:build unix
+
+package task_manager
+
+import (
+	"errors"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// TestConcurrentManagers - несколько TaskManager над одним файлом (как параллельные процессы CLI)
+// не должны терять таски и выдавать одинаковые id.
+func TestConcurrentManagers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	const managers = 4
+	const tasksPerManager = 10
+
+	var wg sync.WaitGroup
+	errs := make(chan error, managers*tasksPerManager)
+	for i := 0; i < managers; i++ {
+		tm, err := NewTaskManager(path)
+		if err != nil {
+			t.Fatalf("Failed to create TaskManager: %v", err)
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < tasksPerManager; j++ {
+				if _, err := tm.AddTask("task", "concurrent"); err != nil {
+					errs <- err
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("AddTask() error = %v", err)
+	}
+
+	tm, err := NewTaskManager(path)
+	if err != nil {
+		t.Fatalf("Failed to reload TaskManager: %v", err)
+	}
+	tasks, err := tm.ListAllTasks()
+	if err != nil {
+		t.Fatalf("ListAllTasks() error = %v", err)
+	}
+	if len(tasks) != managers*tasksPerManager {
+		t.Errorf("got %d tasks, want %d", len(tasks), managers*tasksPerManager)
+	}
+	for i, task := range tasks {
+		if task.TaskId != i+1 {
+			t.Errorf("tasks[%d].TaskId = %d, want %d", i, task.TaskId, i+1)
+		}
+	}
+}
+
+// TestLockTimeout - если блокировку держит другой процесс, изменение должно завершиться ErrLockTimeout.
+func TestLockTimeout(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	holder := NewJSONFileStore(path)
+	unlock, err := holder.Lock(time.Second)
+	if err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+
+	tm, err := NewTaskManager(path)
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+	tm.LockTimeout = 50 * time.Millisecond
+
+	if _, err := tm.AddTask("blocked", ""); !errors.Is(err, ErrLockTimeout) {
+		t.Errorf("AddTask() error = %v, want ErrLockTimeout", err)
+	}
+
+	if err := unlock(); err != nil {
+		t.Fatalf("unlock() error = %v", err)
+	}
+	if _, err := tm.AddTask("unblocked", ""); err != nil {
+		t.Errorf("AddTask() after unlock error = %v", err)
+	}
+}
@@ -1,77 +1,205 @@
 package task_manager
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
-	"sort"
+	"io"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/TaskTrackerCLI/structures"
 )
 
+// TaskManager - CRUD над тасками поверх Store. Безопасен для одновременного использования
+// из нескольких горутин, наружу отдаются только копии тасков
 type TaskManager struct {
-	Tasks    map[int]structures.Task
-	nextId   int
-	FilePath string
+	mu    sync.RWMutex
+	store Store
+	// LockTimeout - сколько ждать блокировку хранилища, занятую другим процессом.
+	// Задается до начала работы с TaskManager
+	LockTimeout time.Duration
+	// HistoryLimit - сколько последних операций можно отменить, 0 отключает историю
+	HistoryLimit int
+	// Actor - кто вносит изменения, попадает в историю тасков. По умолчанию пользователь ОС
+	Actor string
+	// Workflow - статусы и разрешенные переходы между ними. Задается до начала работы с TaskManager
+	Workflow Workflow
 }
 
+// NewTaskManager - создает TaskManager, хранящий таски в json файле filePath
 func NewTaskManager(filePath string) (*TaskManager, error) {
+	return NewTaskManagerWithStore(NewJSONFileStore(filePath))
+}
+
+// NewTaskManagerWithStore - создает TaskManager поверх произвольного хранилища
+func NewTaskManagerWithStore(store Store) (*TaskManager, error) {
 	taskManager := &TaskManager{
-		Tasks:    make(map[int]structures.Task),
-		FilePath: filePath,
-		nextId:   1}
-	if err := taskManager.LoadTasks(); err != nil {
+		store:        store,
+		LockTimeout:  DefaultLockTimeout,
+		HistoryLimit: DefaultHistoryLimit,
+		Actor:        currentActor(),
+		Workflow:     DefaultWorkflow()}
+	if err := taskManager.load(); err != nil {
 		return taskManager, err
 	}
 	return taskManager, nil
 
 }
 
+// mutate - выполняет цикл загрузка-изменение-сохранение под блокировкой хранилища, чтобы
+// параллельно запущенные процессы не затирали изменения друг друга. change работает с переданным
+// store, который запоминает состояние тасков до и после; если ничего не изменилось, сохранять нечего.
+// kind - вид операции для истории отмены, журнала изменений и истории тасков
+func (taskManager *TaskManager) mutate(kind string, change func(store Store) error) (err error) {
+	taskManager.mu.Lock()
+	defer taskManager.mu.Unlock()
+
+	if locker, ok := taskManager.store.(Locker); ok {
+		unlock, err := locker.Lock(taskManager.LockTimeout)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if unlockErr := unlock(); unlockErr != nil && err == nil {
+				err = fmt.Errorf("failed to release tasks lock: %w", unlockErr)
+			}
+		}()
+	}
+
+	if err := taskManager.load(); err != nil {
+		return err
+	}
+	at := time.Now().Format(time.RFC3339)
+	recorder := newRecordingStore(taskManager.store)
+	err = change(recorder)
+	if err == nil {
+		err = recorder.stampHistory(kind, taskManager.Actor, at)
+	}
+	if changes := recorder.taskChanges(); err == nil && (len(changes) > 0 || recorder.metaChanged) {
+		err = taskManager.commit(Operation{
+			Kind:    kind,
+			At:      at,
+			Changes: changes,
+			Meta:    recorder.metaChanges(),
+		})
+	}
+	if err != nil {
+		// перечитываем хранилище, чтобы отбросить частично примененные изменения
+		_ = taskManager.load()
+	}
+	return err
+}
+
+// commit - записывает операцию в историю отмены и сохраняет хранилище
+func (taskManager *TaskManager) commit(op Operation) error {
+	if (len(op.Changes) > 0 || len(op.Meta) > 0) && op.Kind != OperationUndo && op.Kind != OperationRedo {
+		if err := taskManager.pushUndo(op); err != nil {
+			return err
+		}
+	}
+	if recorder, ok := taskManager.store.(OperationStore); ok {
+		if err := recorder.Record(op); err != nil {
+			return err
+		}
+	}
+	return taskManager.store.Save()
+}
+
 // AddTask Метод добавления нового таска с данными(имя, описание)
 func (taskManager *TaskManager) AddTask(name, description string) (int, error) {
+	return taskManager.AddTaskWithValues(name, description, nil)
+}
+
+// AddTaskWithValues - добавляет таск и сразу задает дополнительные поля по тем же ключам, что и UpdateTask
+func (taskManager *TaskManager) AddTaskWithValues(name, description string, values map[string]string) (int, error) {
 	if strings.TrimSpace(name) == "" {
 		return 0, fmt.Errorf("task name must not be empty")
 	}
-	newTask := structures.Task{
-		TaskId:          taskManager.nextId,
-		TaskName:        name,
-		TaskDescription: description,
-		TaskStatus:      "TODO",
-		TaskCreatedAt:   time.Now().Format(time.RFC3339),
-	}
-	taskManager.Tasks[taskManager.nextId] = newTask
-	taskManager.nextId++
-	err := taskManager.SaveTasks()
+	var id int
+	err := taskManager.mutate(OperationAdd, func(store Store) error {
+		var err error
+		id, err = store.NextID()
+		if err != nil {
+			return err
+		}
+		newTask := structures.Task{
+			TaskId:          id,
+			TaskName:        name,
+			TaskDescription: description,
+			TaskStatus:      StatusTodo,
+			TaskPriority:    DefaultPriority,
+			TaskCreatedAt:   time.Now().Format(time.RFC3339),
+		}
+		if err := applyValues(store, &newTask, values); err != nil {
+			return err
+		}
+		return store.Put(newTask)
+	})
 	if err != nil {
 		return 0, err
 	}
-	return taskManager.nextId - 1, nil
+	return id, nil
+}
+
+// GetTask - Метод получения таска по id. Таски из корзины не находятся
+func (taskManager *TaskManager) GetTask(id int) (structures.Task, bool, error) {
+	taskManager.mu.RLock()
+	defer taskManager.mu.RUnlock()
+	return getActiveTask(taskManager.store, id)
+}
+
+// FindTask - таск по id, включая таски из корзины и архива, например чтобы показать их историю
+func (taskManager *TaskManager) FindTask(id int) (structures.Task, bool, error) {
+	taskManager.mu.RLock()
+	defer taskManager.mu.RUnlock()
+	return taskManager.store.Get(id)
 }
 
-// DeleteTask Метод удаления таска с id
+// DeleteTask Метод удаления таска с id: таск вместе с подзадачами переносится в корзину, откуда его можно вернуть
 func (taskManager *TaskManager) DeleteTask(id int) (bool, error) {
-	_, ok := taskManager.Tasks[id]
-	if !ok {
-		return false, nil
-	}
-	delete(taskManager.Tasks, id)
-	err := taskManager.SaveTasks()
+	var found bool
+	err := taskManager.mutate(OperationDelete, func(store Store) error {
+		task, ok, err := getActiveTask(store, id)
+		if err != nil || !ok {
+			return err
+		}
+		found = true
+		return moveToTrash(store, task)
+	})
 	if err != nil {
 		return false, err
 	}
-	return ok, nil
+	return found, nil
 
 }
 
-// UpdateTask - Метод обновления данных(имя, описание) у таски с id
+// UpdateTask - Метод обновления данных(имя, описание, приоритет, срок, оценка) у таски с id
 func (taskManager *TaskManager) UpdateTask(id int, values map[string]string) (bool, error) {
-	task, ok := taskManager.Tasks[id]
-	if !ok {
-		return false, nil
+	var found bool
+	err := taskManager.mutate(OperationUpdate, func(store Store) error {
+		task, ok, err := getActiveTask(store, id)
+		if err != nil || !ok {
+			return err
+		}
+		found = true
+
+		if err := applyValues(store, &task, values); err != nil {
+			return err
+		}
+
+		task.TaskUpdatedAt = time.Now().Format(time.RFC3339)
+		return store.Put(task)
+	})
+	if err != nil {
+		return false, err
 	}
+	return found, nil
+}
 
+// applyValues - задает поля таска по ключам task_name, task_description, task_priority, task_due,
+// task_recur (правило повторения, none прекращает повторы), task_estimate (оценка, none снимает ее), task_tags (метки через запятую, заменяют текущие), task_project (имя проекта), task_parent (id родителя).
+// Остальные ключи игнорируются
+func applyValues(store Store, task *structures.Task, values map[string]string) error {
 	if name, exists := values["task_name"]; exists {
 		task.TaskName = name
 	}
@@ -80,168 +208,279 @@ func (taskManager *TaskManager) UpdateTask(id int, values map[string]string) (bo
 		task.TaskDescription = description
 	}
 
-	task.TaskUpdatedAt = time.Now().Format(time.RFC3339)
-	taskManager.Tasks[id] = task
-	err := taskManager.SaveTasks()
-	if err != nil {
-		return false, err
+	if value, exists := values["task_priority"]; exists {
+		priority, err := ParsePriority(value)
+		if err != nil {
+			return err
+		}
+		task.TaskPriority = priority
 	}
-	return true, nil
-}
 
-func (taskManager *TaskManager) taskStatusHelper(id int, newStatus string) bool {
-	task, ok := taskManager.Tasks[id]
-	if !ok {
-		return false
+	if value, exists := values["task_due"]; exists {
+		due, err := parseDue(value, time.Now())
+		if err != nil {
+			return err
+		}
+		task.TaskDueDate = due
 	}
-	task.TaskStatus = newStatus
-	task.TaskUpdatedAt = time.Now().Format(time.RFC3339)
-	taskManager.Tasks[id] = task
 
-	return true
-}
+	if value, exists := values["task_recur"]; exists {
+		recurrence, due, err := parseRecurrence(value, task.TaskDueDate, time.Now())
+		if err != nil {
+			return err
+		}
+		task.TaskRecurrence = recurrence
+		if task.TaskDueDate == "" {
+			task.TaskDueDate = due
+		}
+	}
 
-// MarkTaskAsDone - Метод для установки статуса "DONE"
-func (taskManager *TaskManager) MarkTaskAsDone(id int) (bool, error) {
-	if !taskManager.taskStatusHelper(id, "DONE") {
-		return false, nil
+	if value, exists := values["task_estimate"]; exists {
+		estimate, err := parseEstimate(value)
+		if err != nil {
+			return err
+		}
+		task.TaskEstimate = estimate
 	}
-	if err := taskManager.SaveTasks(); err != nil {
-		return false, fmt.Errorf("failed to save task status change: %w", err)
+
+	if value, exists := values["task_tags"]; exists {
+		tags, err := ParseTags(value)
+		if err != nil {
+			return err
+		}
+		task.TaskTags = tags
 	}
-	return true, nil
-}
 
-// MarkTaskAsInProgress - Метод для установки статуса "IN_PROGRESS"
-func (taskManager *TaskManager) MarkTaskAsInProgress(id int) (bool, error) {
-	if !taskManager.taskStatusHelper(id, "IN_PROGRESS") {
-		return false, nil
+	if value, exists := values["task_project"]; exists {
+		projectId, err := resolveProject(store, value)
+		if err != nil {
+			return err
+		}
+		task.TaskProjectId = projectId
 	}
-	if err := taskManager.SaveTasks(); err != nil {
-		return false, fmt.Errorf("failed to save task status change: %w", err)
+
+	if value, exists := values["task_parent"]; exists {
+		parentId, err := resolveParent(store, task.TaskId, value)
+		if err != nil {
+			return err
+		}
+		task.TaskParentId = parentId
 	}
-	return true, nil
+	return nil
 }
 
-// MarkTaskAsTodo - Метод для установки статуса Toдo
-func (taskManager *TaskManager) MarkTaskAsTodo(id int) (bool, error) {
-	if !taskManager.taskStatusHelper(id, "TODO") {
-		return false, nil
+// StatusChange - последствия смены статуса, о которых стоит сказать пользователю
+type StatusChange struct {
+	// Blockers - незавершенные таски, от которых зависит таск, переведенный в работу
+	Blockers []int
+	// NextOccurrence - id следующего экземпляра серии, созданного при выполнении повторяющегося таска, 0 если его нет
+	NextOccurrence int
+}
+
+// SetStatus - переводит таск в статус status, если рабочий процесс разрешает такой переход
+// (иначе ErrTransitionNotAllowed). Пока у таска есть открытые подзадачи, DONE не ставится и
+// возвращается ErrOpenSubtasks (см. MarkTaskTreeAsDone). У выполненного повторяющегося таска
+// появляется следующий экземпляр серии. Если таск переведен в работу (не TODO и не DONE), а от него
+// еще не завершены таски, от которых он зависит, их id возвращаются как предупреждение: статус все равно меняется
+func (taskManager *TaskManager) SetStatus(id int, status string) (bool, StatusChange, error) {
+	var change StatusChange
+	status = NormalizeStatus(status)
+	if !taskManager.Workflow.HasStatus(status) {
+		return false, change, fmt.Errorf("unknown status %q, use one of %s", status, strings.Join(taskManager.Workflow.Statuses, ", "))
 	}
-	if err := taskManager.SaveTasks(); err != nil {
-		return false, fmt.Errorf("failed to save task status change: %w", err)
+	var found bool
+	err := taskManager.mutate(OperationStatus, func(store Store) error {
+		task, ok, err := getActiveTask(store, id)
+		if err != nil || !ok {
+			return err
+		}
+		found = true
+		if err := taskManager.Workflow.checkTransition(id, task.TaskStatus, status); err != nil {
+			return err
+		}
+		if status == StatusDone {
+			open, err := openSubtasks(store, id)
+			if err != nil {
+				return err
+			}
+			if len(open) > 0 {
+				return fmt.Errorf("%w: %d of them are not DONE", ErrOpenSubtasks, len(open))
+			}
+		}
+		if status != StatusTodo && status != StatusDone {
+			if change.Blockers, err = taskBlockers(store, id); err != nil {
+				return err
+			}
+		}
+		task.TaskStatus = status
+		task.TaskUpdatedAt = time.Now().Format(time.RFC3339)
+		if status == StatusDone {
+			if change.NextOccurrence, err = scheduleNextOccurrence(store, &task, time.Now()); err != nil {
+				return err
+			}
+		}
+		return store.Put(task)
+	})
+	if err != nil {
+		return false, StatusChange{}, fmt.Errorf("failed to save task status change: %w", err)
 	}
-	return true, nil
+	return found, change, nil
 }
 
-func (taskManager *TaskManager) filterTaskByStatus(status string) []structures.Task {
-	result := make([]structures.Task, 0, len(taskManager.Tasks))
+// MarkTaskAsDone - Метод для установки статуса "DONE", см. SetStatus
+func (taskManager *TaskManager) MarkTaskAsDone(id int) (bool, error) {
+	ok, _, err := taskManager.SetStatus(id, StatusDone)
+	return ok, err
+}
+
+// MarkTaskAsInProgress - Метод для установки статуса "IN_PROGRESS", см. SetStatus. Возвращает
+// id незавершенных тасков, от которых зависит таск
+func (taskManager *TaskManager) MarkTaskAsInProgress(id int) (bool, []int, error) {
+	ok, change, err := taskManager.SetStatus(id, StatusInProgress)
+	return ok, change.Blockers, err
+}
+
+// MarkTaskAsTodo - Метод для установки статуса Toдo, см. SetStatus
+func (taskManager *TaskManager) MarkTaskAsTodo(id int) (bool, error) {
+	ok, _, err := taskManager.SetStatus(id, StatusTodo)
+	return ok, err
+}
+
+func (taskManager *TaskManager) filterTaskByStatus(status string) ([]structures.Task, error) {
+	taskManager.mu.RLock()
+	defer taskManager.mu.RUnlock()
 
 	filterALL := status == "ALL"
-	for _, task := range taskManager.Tasks {
-		if filterALL || task.TaskStatus == status {
-			result = append(result, task)
+	var result []structures.Task
+	filterStore, canFilter := taskManager.store.(StatusFilterStore)
+	if canFilter && !filterALL {
+		tasks, err := filterStore.ListByStatus(status)
+		if err != nil {
+			return nil, err
+		}
+		result = tasks
+	} else {
+		tasks, err := listActive(taskManager.store)
+		if err != nil {
+			return nil, err
+		}
+		result = make([]structures.Task, 0, len(tasks))
+		for _, task := range tasks {
+			if filterALL || task.TaskStatus == status {
+				result = append(result, task)
+			}
 		}
 	}
-	sort.Slice(result, func(i, j int) bool {
-		return result[i].TaskId < result[j].TaskId
-	})
-	return result
+	result = activeTasks(result)
+	sortTasks(result)
+	return result, nil
+}
+
+// ListTasksByStatus - таски рабочего набора в статусе status из рабочего процесса
+func (taskManager *TaskManager) ListTasksByStatus(status string) ([]structures.Task, error) {
+	status = NormalizeStatus(status)
+	if !taskManager.Workflow.HasStatus(status) {
+		return nil, fmt.Errorf("unknown status %q, use all or one of %s", status, strings.Join(taskManager.Workflow.Statuses, ", "))
+	}
+	return taskManager.filterTaskByStatus(status)
 }
 
-func (taskManager *TaskManager) ListDoneTasks() []structures.Task {
-	return taskManager.filterTaskByStatus("DONE")
+func (taskManager *TaskManager) ListDoneTasks() ([]structures.Task, error) {
+	return taskManager.filterTaskByStatus(StatusDone)
 }
 
-func (taskManager *TaskManager) ListTodoTasks() []structures.Task {
-	return taskManager.filterTaskByStatus("TODO")
+func (taskManager *TaskManager) ListTodoTasks() ([]structures.Task, error) {
+	return taskManager.filterTaskByStatus(StatusTodo)
 }
 
-func (taskManager *TaskManager) ListInProgressTasks() []structures.Task {
-	return taskManager.filterTaskByStatus("IN_PROGRESS")
+func (taskManager *TaskManager) ListInProgressTasks() ([]structures.Task, error) {
+	return taskManager.filterTaskByStatus(StatusInProgress)
 }
 
-func (taskManager *TaskManager) ListAllTasks() []structures.Task {
+func (taskManager *TaskManager) ListAllTasks() ([]structures.Task, error) {
 	return taskManager.filterTaskByStatus("ALL")
 }
 
-// SaveTasks - метод для сохранения созданных, обновленных, удаленных тасков в json
-func (taskManager *TaskManager) SaveTasks() error {
-	tasks, err := json.Marshal(taskManager.Tasks)
-	if err != nil {
-		return fmt.Errorf("failed to write tasks file: %w", err)
-	}
-	err = os.WriteFile(taskManager.FilePath, tasks, 0644)
-	if err != nil {
-		return fmt.Errorf("failed to write tasks file: %w", err)
+// Close - освобождает ресурсы хранилища, если они есть
+func (taskManager *TaskManager) Close() error {
+	taskManager.mu.Lock()
+	defer taskManager.mu.Unlock()
+	if closer, ok := taskManager.store.(io.Closer); ok {
+		return closer.Close()
 	}
 	return nil
 }
 
-// LoadTasks - метод для загрузки тасков из json файла
-func (taskManager *TaskManager) LoadTasks() error {
-	fileContent, err := os.ReadFile(taskManager.FilePath)
-	if err != nil {
-		if os.IsNotExist(err) {
-			taskManager.nextId = 1
-			return nil
-		}
-		return fmt.Errorf("failed to read tasks: %w", err)
-	}
+// SaveTasks - метод для сохранения созданных, обновленных, удаленных тасков в хранилище
+func (taskManager *TaskManager) SaveTasks() error {
+	taskManager.mu.Lock()
+	defer taskManager.mu.Unlock()
+	return taskManager.store.Save()
+}
 
-	if len(fileContent) == 0 {
-		taskManager.nextId = 1
-		return nil
-	}
+// LoadTasks - метод для загрузки тасков из хранилища
+func (taskManager *TaskManager) LoadTasks() error {
+	taskManager.mu.Lock()
+	defer taskManager.mu.Unlock()
+	return taskManager.load()
+}
 
-	err = json.Unmarshal(fileContent, &taskManager.Tasks)
-	if err != nil {
-		return fmt.Errorf("failed to unmarshal tasks: %w", err)
-	}
-	maxID := 0
-	for _, task := range taskManager.Tasks {
-		if task.TaskId > maxID {
-			maxID = task.TaskId
-		}
-	}
-	taskManager.nextId = maxID + 1
-	return nil
+func (taskManager *TaskManager) load() error {
+	return taskManager.store.Load()
 }
 
 // SearchTasks - Метод, позволяющий находить нужные таски по подстрокам
-func (taskManager *TaskManager) SearchTasks(query string) []structures.Task {
-	tasks := make([]structures.Task, 0, len(taskManager.Tasks))
-	for _, task := range taskManager.Tasks {
+func (taskManager *TaskManager) SearchTasks(query string) ([]structures.Task, error) {
+	taskManager.mu.RLock()
+	defer taskManager.mu.RUnlock()
+
+	if searchStore, ok := taskManager.store.(SearchStore); ok {
+		tasks, err := searchStore.Search(query)
+		if err != nil {
+			return nil, err
+		}
+		sortTasks(tasks)
+		return tasks, nil
+	}
+	all, err := listActive(taskManager.store)
+	if err != nil {
+		return nil, err
+	}
+	tasks := make([]structures.Task, 0, len(all))
+	for _, task := range all {
 		if strings.Contains(strings.ToLower(task.TaskName), strings.ToLower(query)) || strings.Contains(strings.ToLower(task.TaskDescription), strings.ToLower(query)) {
 			tasks = append(tasks, task)
 		}
 	}
-	if len(tasks) == 0 {
-		return tasks
-	}
-	return tasks
+	sortTasks(tasks)
+	return tasks, nil
 
 }
 
-// CleanDoneTasks - очищает таски со статусом DONE
+// CleanDoneTasks - убирает таски со статусом DONE в архив
 func (taskManager *TaskManager) CleanDoneTasks() (int, error) {
-	var idsToDelete []int
-	for _, task := range taskManager.Tasks {
-		if task.TaskStatus == "DONE" {
-			idsToDelete = append(idsToDelete, task.TaskId)
+	var count int
+	err := taskManager.mutate(OperationClean, func(store Store) error {
+		tasks, err := listActive(store)
+		if err != nil {
+			return err
+		}
+		var tasksToArchive []structures.Task
+		for _, task := range tasks {
+			if task.TaskStatus == StatusDone {
+				tasksToArchive = append(tasksToArchive, task)
+			}
 		}
-	}
-
-	count := len(idsToDelete)
-	if count == 0 {
-		return 0, nil
-	}
-
-	for _, id := range idsToDelete {
-		delete(taskManager.Tasks, id)
-	}
 
-	if err := taskManager.SaveTasks(); err != nil {
+		for _, task := range tasksToArchive {
+			if err := moveToArchive(store, task); err != nil {
+				return err
+			}
+		}
+		count = len(tasksToArchive)
+		return nil
+	})
+	if err != nil {
 		return 0, fmt.Errorf("failed to save tasks: %w", err)
 	}
 	return count, nil
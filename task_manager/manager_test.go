@@ -7,7 +7,7 @@ import (
 
 // TestAddTask проверяет успешное добавление и обработку ошибок для пустых полей.
 func TestAddTask(t *testing.T) {
-	tmpFile, err := os.CreateTemp("", "task-test-*.json")
+	tmpFile, err := os.CreateTemp(t.TempDir(), "task-test-*.json")
 	if err != nil {
 		t.Fatalf("Failed to create temp file: %v", err)
 	}
@@ -60,7 +60,7 @@ func TestAddTask(t *testing.T) {
 					t.Errorf("AddTask returned invalid ID: %d, want > 0", id)
 				}
 
-				task, ok := tm.Tasks[id]
+				task, ok, _ := tm.GetTask(id)
 				if !ok || task.TaskName != tt.inputName || task.TaskDescription != tt.inputDesc {
 					t.Errorf("Task not correctly stored or retrieved from map")
 				}
@@ -92,7 +92,7 @@ func TestDeleteTask(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tmpFile, _ := os.CreateTemp("", "task-test-*.json")
+			tmpFile, _ := os.CreateTemp(t.TempDir(), "task-test-*.json")
 			tmpFilePath := tmpFile.Name()
 			err := tmpFile.Close()
 			if err != nil {
@@ -125,7 +125,7 @@ func TestDeleteTask(t *testing.T) {
 				t.Errorf("DeleteTask() ok = %v, wantOK %v", ok, tt.wantOK)
 			}
 			if ok {
-				if _, exists := tm.Tasks[tt.id]; exists {
+				if _, exists, _ := tm.GetTask(tt.id); exists {
 					t.Errorf("DeleteTask() deleted task with id %d", tt.id)
 				}
 			}
@@ -192,7 +192,7 @@ func TestUpdateTask(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			tmpFile, err := os.CreateTemp("", "task-update-*.json")
+			tmpFile, err := os.CreateTemp(t.TempDir(), "task-update-*.json")
 			if err != nil {
 				t.Fatalf("Setup failed: %v", err)
 			}
@@ -230,7 +230,7 @@ func TestUpdateTask(t *testing.T) {
 			}
 
 			if ok {
-				updatedTask, exists := tm.Tasks[tt.taskID]
+				updatedTask, exists, _ := tm.GetTask(tt.taskID)
 				if !exists {
 					t.Fatalf("Task unexpectedly disappeared after successful update")
 				}
@@ -267,12 +267,12 @@ func TestMarkTaskStatus(t *testing.T) {
 			taskID:          1,
 			setupName:       "Original Name",
 			setupDesc:       "Original Description",
-			inputStatus:     "DONE",
+			inputStatus:     StatusDone,
 			wantOK:          true,
 			wantErr:         false,
 			wantFinalName:   "Original Name",
 			wantFinalDesc:   "Original Description",
-			wantFinalStatus: "DONE",
+			wantFinalStatus: StatusDone,
 		},
 
 		{
@@ -280,12 +280,12 @@ func TestMarkTaskStatus(t *testing.T) {
 			taskID:          1,
 			setupName:       "Original Name",
 			setupDesc:       "Original Description",
-			inputStatus:     "TODO",
+			inputStatus:     StatusTodo,
 			wantOK:          true,
 			wantErr:         false,
 			wantFinalName:   "Original Name",
 			wantFinalDesc:   "Original Description",
-			wantFinalStatus: "TODO",
+			wantFinalStatus: StatusTodo,
 		},
 
 		{
@@ -293,12 +293,12 @@ func TestMarkTaskStatus(t *testing.T) {
 			taskID:          1,
 			setupName:       "Original Name",
 			setupDesc:       "Original Description",
-			inputStatus:     "IN_PROGRESS",
+			inputStatus:     StatusInProgress,
 			wantOK:          true,
 			wantErr:         false,
 			wantFinalName:   "Original Name",
 			wantFinalDesc:   "Original Description",
-			wantFinalStatus: "IN_PROGRESS",
+			wantFinalStatus: StatusInProgress,
 		},
 
 		{
@@ -306,7 +306,7 @@ func TestMarkTaskStatus(t *testing.T) {
 			taskID:          999,
 			setupName:       "N/A",
 			setupDesc:       "N/A",
-			inputStatus:     "DONE",
+			inputStatus:     StatusDone,
 			wantOK:          false,
 			wantErr:         false,
 			wantFinalName:   "",
@@ -317,7 +317,7 @@ func TestMarkTaskStatus(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 
-			tmpFile, err := os.CreateTemp("", "task-update-*.json")
+			tmpFile, err := os.CreateTemp(t.TempDir(), "task-update-*.json")
 			if err != nil {
 				t.Fatalf("Setup failed: %v", err)
 			}
@@ -348,11 +348,11 @@ func TestMarkTaskStatus(t *testing.T) {
 			var markErr error
 
 			switch tt.inputStatus {
-			case "DONE":
+			case StatusDone:
 				ok, markErr = tm.MarkTaskAsDone(tt.taskID)
-			case "IN_PROGRESS":
-				ok, markErr = tm.MarkTaskAsInProgress(tt.taskID)
-			case "TODO":
+			case StatusInProgress:
+				ok, _, markErr = tm.MarkTaskAsInProgress(tt.taskID)
+			case StatusTodo:
 				ok, markErr = tm.MarkTaskAsTodo(tt.taskID)
 			default:
 				t.Fatalf("Test setup error: Invalid input status %s", tt.inputStatus)
@@ -367,7 +367,7 @@ func TestMarkTaskStatus(t *testing.T) {
 			}
 
 			if ok {
-				updatedTask, exists := tm.Tasks[tt.taskID]
+				updatedTask, exists, _ := tm.GetTask(tt.taskID)
 				if !exists {
 					t.Fatalf("Task unexpectedly disappeared after successful update status")
 				}
@@ -395,14 +395,14 @@ func TestCleanDoneTasks(t *testing.T) {
 	}{
 		{
 			name:           "Success: Deleting multiple tasks, keeping one",
-			tasksToSetup:   map[int]string{1: "DONE", 2: "TODO", 3: "DONE", 4: "IN_PROGRESS"},
+			tasksToSetup:   map[int]string{1: StatusDone, 2: StatusTodo, 3: StatusDone, 4: StatusInProgress},
 			wantCount:      2,
 			tasksRemaining: 2,
 			wantErr:        false,
 		},
 		{
 			name:           "Success: Deleting all tasks",
-			tasksToSetup:   map[int]string{1: "DONE", 2: "DONE"},
+			tasksToSetup:   map[int]string{1: StatusDone, 2: StatusDone},
 			wantCount:      2,
 			tasksRemaining: 0,
 			wantErr:        false,
@@ -416,7 +416,7 @@ func TestCleanDoneTasks(t *testing.T) {
 		},
 		{
 			name:           "Success: Nothing to delete",
-			tasksToSetup:   map[int]string{1: "TODO", 2: "IN_PROGRESS"},
+			tasksToSetup:   map[int]string{1: StatusTodo, 2: StatusInProgress},
 			wantCount:      0,
 			tasksRemaining: 2,
 			wantErr:        false,
@@ -426,7 +426,7 @@ func TestCleanDoneTasks(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 
-			tmpFile, err := os.CreateTemp("", "task-clean-*.json")
+			tmpFile, err := os.CreateTemp(t.TempDir(), "task-clean-*.json")
 			if err != nil {
 				t.Fatalf("Setup failed: %v", err)
 			}
@@ -454,11 +454,16 @@ func TestCleanDoneTasks(t *testing.T) {
 					t.Fatalf("Setup AddTask failed: %v", err)
 				}
 
-				if task, ok := tm.Tasks[newID]; ok {
+				if task, ok, _ := tm.GetTask(newID); ok {
 					// Изменяем копию
 					task.TaskStatus = status
 
-					tm.Tasks[newID] = task
+					if err := tm.store.Put(task); err != nil {
+						t.Fatalf("Setup status change failed: %v", err)
+					}
+					if err := tm.SaveTasks(); err != nil {
+						t.Fatalf("Setup save failed: %v", err)
+					}
 				}
 			}
 
@@ -472,12 +477,16 @@ func TestCleanDoneTasks(t *testing.T) {
 				t.Errorf("CleanDoneTasks() returned count %d, want %d", count, tt.wantCount)
 			}
 
-			if len(tm.Tasks) != tt.tasksRemaining {
-				t.Errorf("CleanDoneTasks() left %d tasks, want %d", len(tm.Tasks), tt.tasksRemaining)
+			remaining, err := tm.ListAllTasks()
+			if err != nil {
+				t.Fatalf("ListAllTasks() error = %v", err)
+			}
+			if len(remaining) != tt.tasksRemaining {
+				t.Errorf("CleanDoneTasks() left %d tasks, want %d", len(remaining), tt.tasksRemaining)
 			}
 
-			for _, task := range tm.Tasks {
-				if task.TaskStatus == "DONE" {
+			for _, task := range remaining {
+				if task.TaskStatus == StatusDone {
 					t.Errorf("CleanDoneTasks failed: Task ID %d with status DONE was not deleted.", task.TaskId)
 				}
 			}
@@ -0,0 +1,76 @@
+package task_manager
+
+import (
+	"encoding/json"
+
+	"github.com/TaskTrackerCLI/structures"
+)
+
+// MemoryStore - хранилище тасков в памяти, ничего не сохраняет на диск. Удобно для тестов
+type MemoryStore struct {
+	tasks  map[int]structures.Task
+	meta   map[string]json.RawMessage
+	nextID int
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		tasks:  make(map[int]structures.Task),
+		meta:   make(map[string]json.RawMessage),
+		nextID: 1,
+	}
+}
+
+func (store *MemoryStore) Load() error {
+	return nil
+}
+
+func (store *MemoryStore) Save() error {
+	return nil
+}
+
+func (store *MemoryStore) Get(id int) (structures.Task, bool, error) {
+	task, ok := store.tasks[id]
+	return task.Clone(), ok, nil
+}
+
+func (store *MemoryStore) Put(task structures.Task) error {
+	store.tasks[task.TaskId] = task.Clone()
+	if task.TaskId >= store.nextID {
+		store.nextID = task.TaskId + 1
+	}
+	return nil
+}
+
+func (store *MemoryStore) Delete(id int) error {
+	delete(store.tasks, id)
+	return nil
+}
+
+func (store *MemoryStore) List() ([]structures.Task, error) {
+	tasks := make([]structures.Task, 0, len(store.tasks))
+	for _, task := range store.tasks {
+		tasks = append(tasks, task.Clone())
+	}
+	return tasks, nil
+}
+
+func (store *MemoryStore) NextID() (int, error) {
+	id := store.nextID
+	store.nextID++
+	return id, nil
+}
+
+func (store *MemoryStore) Meta(key string) (json.RawMessage, bool, error) {
+	value, ok := store.meta[key]
+	return value, ok, nil
+}
+
+func (store *MemoryStore) SetMeta(key string, value json.RawMessage) error {
+	if value == nil {
+		delete(store.meta, key)
+		return nil
+	}
+	store.meta[key] = value
+	return nil
+}
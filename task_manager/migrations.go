@@ -0,0 +1,238 @@
+package task_manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/TaskTrackerCLI/structures"
+)
+
+// CurrentSchemaVersion - версия формата json файла тасков, которую пишет JSONFileStore
+const CurrentSchemaVersion = 4
+
+// taskFile - содержимое json файла тасков: версия схемы, метаданные и сами таски
+type taskFile struct {
+	SchemaVersion int    `json:"schema_version"`
+	CreatedAt     string `json:"created_at"`
+	NextId        int    `json:"next_id"`
+	// JournalSeq - номер последнего события журнала, учтенного в файле (см. JournalStore)
+	JournalSeq int64                   `json:"journal_seq,omitempty"`
+	Tasks      map[int]structures.Task `json:"tasks"`
+	// Archived - id тасков, которые лежат в файле архива, и когда их туда убрали (см. JSONFileStore)
+	Archived map[int]string `json:"archived,omitempty"`
+	// Meta - служебные данные TaskManager по ключам, например история отмены
+	Meta map[string]json.RawMessage `json:"meta"`
+}
+
+// archiveFile - содержимое файла архива рядом с файлом тасков. Появился в версии схемы 4
+type archiveFile struct {
+	SchemaVersion int                     `json:"schema_version"`
+	Tasks         map[int]structures.Task `json:"tasks"`
+}
+
+// fileDocument - файл тасков в сыром виде, с которым работают миграции
+type fileDocument map[string]json.RawMessage
+
+// fileMigration - переводит документ с версии схемы from на from+1
+type fileMigration struct {
+	from    int
+	migrate func(document fileDocument) (fileDocument, error)
+}
+
+// fileMigrations - цепочка миграций, по одной на каждую версию схемы начиная с 0
+var fileMigrations = []fileMigration{
+	{from: 0, migrate: migrateBareTaskMap},
+	{from: 1, migrate: addMetaSection},
+	{from: 2, migrate: addTaskPriority},
+	{from: 3, migrate: separateArchive},
+}
+
+// taskMigrations - то же, что fileMigrations, но для одного уже разобранного таска. Нужны там, где таски
+// хранятся вне документа файла, например в событиях журнала. Версии без записи таски не меняют
+var taskMigrations = map[int]func(task *structures.Task){
+	2: defaultTaskPriority,
+}
+
+// migrateTask - доводит таск, записанный схемой version, до CurrentSchemaVersion
+func migrateTask(task *structures.Task, version int) {
+	for ; version < CurrentSchemaVersion; version++ {
+		if migrate, ok := taskMigrations[version]; ok {
+			migrate(task)
+		}
+	}
+}
+
+// decodeTaskFile - разбирает json файл тасков любой поддерживаемой версии, применяя недостающие миграции
+func decodeTaskFile(content []byte) (taskFile, error) {
+	if len(content) == 0 {
+		return newTaskFile(), nil
+	}
+
+	var document fileDocument
+	if err := json.Unmarshal(content, &document); err != nil {
+		return taskFile{}, fmt.Errorf("failed to unmarshal tasks: %w", err)
+	}
+	if document == nil {
+		return taskFile{}, fmt.Errorf("failed to unmarshal tasks: file is not a json object")
+	}
+
+	version, err := documentVersion(document)
+	if err != nil {
+		return taskFile{}, err
+	}
+	if version > CurrentSchemaVersion {
+		return taskFile{}, fmt.Errorf("tasks file has schema version %d, this TaskTracker supports up to %d", version, CurrentSchemaVersion)
+	}
+	for _, migration := range fileMigrations {
+		if migration.from != version {
+			continue
+		}
+		document, err = migration.migrate(document)
+		if err != nil {
+			return taskFile{}, fmt.Errorf("failed to migrate tasks file from version %d: %w", version, err)
+		}
+		version++
+	}
+
+	raw, err := json.Marshal(document)
+	if err != nil {
+		return taskFile{}, fmt.Errorf("failed to unmarshal tasks: %w", err)
+	}
+	var file taskFile
+	if err := json.Unmarshal(raw, &file); err != nil {
+		return taskFile{}, fmt.Errorf("failed to unmarshal tasks: %w", err)
+	}
+	if file.Tasks == nil {
+		file.Tasks = make(map[int]structures.Task)
+	}
+	if file.Meta == nil {
+		file.Meta = make(map[string]json.RawMessage)
+	}
+	// next_id мог быть испорчен ручной правкой файла, выдавать id существующих тасков нельзя
+	for id := range file.Tasks {
+		if id >= file.NextId {
+			file.NextId = id + 1
+		}
+	}
+	return file, nil
+}
+
+// documentVersion - версия схемы документа. Файлы без schema_version - это исходный формат, голая карта тасков
+func documentVersion(document fileDocument) (int, error) {
+	raw, ok := document["schema_version"]
+	if !ok {
+		return 0, nil
+	}
+	var version int
+	if err := json.Unmarshal(raw, &version); err != nil {
+		return 0, fmt.Errorf("failed to read tasks file schema version: %w", err)
+	}
+	return version, nil
+}
+
+func newTaskFile() taskFile {
+	return taskFile{
+		SchemaVersion: CurrentSchemaVersion,
+		CreatedAt:     time.Now().Format(time.RFC3339),
+		NextId:        1,
+		Tasks:         make(map[int]structures.Task),
+		Meta:          make(map[string]json.RawMessage),
+	}
+}
+
+// migrateBareTaskMap - 0 -> 1: голая карта id -> таск оборачивается в конверт с версией и метаданными.
+// Дата создания файла берется по самому старому таску
+func migrateBareTaskMap(document fileDocument) (fileDocument, error) {
+	tasks := make(map[int]structures.Task, len(document))
+	for key, raw := range document {
+		var task structures.Task
+		if err := json.Unmarshal(raw, &task); err != nil {
+			return nil, fmt.Errorf("task %s: %w", key, err)
+		}
+		id, err := strconv.Atoi(key)
+		if err != nil {
+			return nil, fmt.Errorf("invalid task key %q", key)
+		}
+		tasks[id] = task
+	}
+
+	maxID := 0
+	createdAt := ""
+	for _, task := range tasks {
+		if task.TaskId > maxID {
+			maxID = task.TaskId
+		}
+		if task.TaskCreatedAt != "" && (createdAt == "" || task.TaskCreatedAt < createdAt) {
+			createdAt = task.TaskCreatedAt
+		}
+	}
+	if createdAt == "" {
+		createdAt = time.Now().Format(time.RFC3339)
+	}
+
+	migrated := fileDocument{}
+	for key, value := range map[string]any{
+		"schema_version": 1,
+		"created_at":     createdAt,
+		"next_id":        maxID + 1,
+		"tasks":          tasks,
+	} {
+		raw, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		migrated[key] = raw
+	}
+	return migrated, nil
+}
+
+// addMetaSection - 1 -> 2: появился раздел meta для служебных данных (история отмены и т.п.)
+func addMetaSection(document fileDocument) (fileDocument, error) {
+	if _, ok := document["meta"]; !ok {
+		document["meta"] = json.RawMessage(`{}`)
+	}
+	document["schema_version"] = json.RawMessage(`2`)
+	return document, nil
+}
+
+// addTaskPriority - 2 -> 3: у тасков появился приоритет, существующие получают DefaultPriority
+func addTaskPriority(document fileDocument) (fileDocument, error) {
+	var tasks map[string]map[string]json.RawMessage
+	if raw, ok := document["tasks"]; ok {
+		if err := json.Unmarshal(raw, &tasks); err != nil {
+			return nil, fmt.Errorf("failed to read tasks: %w", err)
+		}
+	}
+	for _, task := range tasks {
+		// миграция 0 -> 1 пишет таски текущей структурой, поэтому поле может уже быть, но пустым
+		if raw, ok := task["task_priority"]; !ok || isJSONNull(raw) || string(raw) == `""` {
+			task["task_priority"] = json.RawMessage(`"` + DefaultPriority + `"`)
+		}
+	}
+	if tasks != nil {
+		raw, err := json.Marshal(tasks)
+		if err != nil {
+			return nil, err
+		}
+		document["tasks"] = raw
+	}
+	document["schema_version"] = json.RawMessage(`3`)
+	return document, nil
+}
+
+// separateArchive - 3 -> 4: таски из архива хранятся в отдельном файле. Документ переносить нечего,
+// архивные таски переезжают при загрузке (см. JSONFileStore.Load), а версия не дает старым
+// версиям TaskTracker открыть файл и не увидеть архив
+func separateArchive(document fileDocument) (fileDocument, error) {
+	document["schema_version"] = json.RawMessage(`4`)
+	return document, nil
+}
+
+// defaultTaskPriority - 2 -> 3 для одного таска, см. addTaskPriority
+func defaultTaskPriority(task *structures.Task) {
+	if task.TaskPriority == "" {
+		task.TaskPriority = DefaultPriority
+	}
+}
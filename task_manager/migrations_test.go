@@ -0,0 +1,168 @@
+package task_manager
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// TestDecodeTaskFile - проверяет разбор файлов каждой версии схемы и миграции между ними.
+func TestDecodeTaskFile(t *testing.T) {
+	tests := []struct {
+		name          string
+		content       string
+		wantErr       string
+		wantTasks     int
+		wantNextID    int
+		wantCreatedAt string
+		// wantPriorities - ожидаемые приоритеты по id, остальные таски должны получить DefaultPriority
+		wantPriorities map[int]string
+	}{
+		{
+			name:       "Empty file",
+			content:    "",
+			wantTasks:  0,
+			wantNextID: 1,
+		},
+		{
+			name:       "Version 0: empty bare map",
+			content:    `{}`,
+			wantTasks:  0,
+			wantNextID: 1,
+		},
+		{
+			name: "Version 0: bare map of tasks",
+			content: `{
+				"1": {"task_id": 1, "task_name": "a", "task_status": "TODO", "task_created_at": "2025-02-01T10:00:00Z"},
+				"7": {"task_id": 7, "task_name": "b", "task_status": "DONE", "task_created_at": "2025-01-15T10:00:00Z"}
+			}`,
+			wantTasks:     2,
+			wantNextID:    8,
+			wantCreatedAt: "2025-01-15T10:00:00Z",
+		},
+		{
+			name:    "Version 0: invalid task key",
+			content: `{"one": {"task_id": 1, "task_name": "a"}}`,
+			wantErr: "invalid task key",
+		},
+		{
+			name: "Version 1",
+			content: `{"schema_version": 1, "created_at": "2025-03-01T00:00:00Z", "next_id": 42,
+				"tasks": {"3": {"task_id": 3, "task_name": "c", "task_status": "TODO"}}}`,
+			wantTasks:     1,
+			wantNextID:    42,
+			wantCreatedAt: "2025-03-01T00:00:00Z",
+		},
+		{
+			name: "Version 2 keeps meta",
+			content: `{"schema_version": 2, "created_at": "2025-03-01T00:00:00Z", "next_id": 2,
+				"tasks": {"1": {"task_id": 1, "task_name": "a", "task_status": "TODO"}}, "meta": {"undo_history": {"undo": []}}}`,
+			wantTasks:     1,
+			wantNextID:    2,
+			wantCreatedAt: "2025-03-01T00:00:00Z",
+		},
+		{
+			name: "Version 3 keeps priority",
+			content: `{"schema_version": 3, "created_at": "2025-03-01T00:00:00Z", "next_id": 3, "tasks": {
+				"1": {"task_id": 1, "task_name": "a", "task_status": "TODO", "task_priority": "CRITICAL"},
+				"2": {"task_id": 2, "task_name": "b", "task_status": "TODO", "task_priority": "LOW"}}, "meta": {}}`,
+			wantTasks:      2,
+			wantNextID:     3,
+			wantCreatedAt:  "2025-03-01T00:00:00Z",
+			wantPriorities: map[int]string{1: PriorityCritical, 2: PriorityLow},
+		},
+		{
+			name:    "Version from the future",
+			content: `{"schema_version": 99, "tasks": {}}`,
+			wantErr: "schema version 99",
+		},
+		{
+			name:    "Not an object",
+			content: `[1, 2, 3]`,
+			wantErr: "failed to unmarshal tasks",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file, err := decodeTaskFile([]byte(tt.content))
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("decodeTaskFile() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("decodeTaskFile() error = %v", err)
+			}
+			if file.SchemaVersion != CurrentSchemaVersion {
+				t.Errorf("SchemaVersion = %d, want %d", file.SchemaVersion, CurrentSchemaVersion)
+			}
+			if len(file.Tasks) != tt.wantTasks {
+				t.Errorf("got %d tasks, want %d", len(file.Tasks), tt.wantTasks)
+			}
+			if file.NextId != tt.wantNextID {
+				t.Errorf("NextId = %d, want %d", file.NextId, tt.wantNextID)
+			}
+			if tt.wantCreatedAt != "" && file.CreatedAt != tt.wantCreatedAt {
+				t.Errorf("CreatedAt = %s, want %s", file.CreatedAt, tt.wantCreatedAt)
+			}
+			if file.CreatedAt == "" {
+				t.Errorf("CreatedAt must be filled")
+			}
+			if file.Meta == nil {
+				t.Errorf("Meta must be initialized")
+			}
+			for id, task := range file.Tasks {
+				want, ok := tt.wantPriorities[id]
+				if !ok {
+					want = DefaultPriority
+				}
+				if task.TaskPriority != want {
+					t.Errorf("task %d priority = %q, want %q", id, task.TaskPriority, want)
+				}
+			}
+		})
+	}
+}
+
+// TestJSONFileStoreUpgradesBareMap - файл старого формата читается и переписывается в текущем формате.
+func TestJSONFileStoreUpgradesBareMap(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	legacy := `{"1": {"task_id": 1, "task_name": "legacy", "task_description": "", "task_status": "TODO", "task_created_at": "2025-01-01T00:00:00Z", "task_updated_at": ""}}`
+	if err := os.WriteFile(path, []byte(legacy), 0644); err != nil {
+		t.Fatalf("Failed to write legacy file: %v", err)
+	}
+
+	tm, err := NewTaskManager(path)
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+	if task, ok, _ := tm.GetTask(1); !ok || task.TaskName != "legacy" {
+		t.Fatalf("legacy task was not loaded: %+v", task)
+	}
+	if _, err := tm.AddTask("new", ""); err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read tasks file: %v", err)
+	}
+	var document map[string]json.RawMessage
+	if err := json.Unmarshal(content, &document); err != nil {
+		t.Fatalf("Saved file is not a json object: %v", err)
+	}
+	if string(document["schema_version"]) != strconv.Itoa(CurrentSchemaVersion) {
+		t.Errorf("schema_version = %s, want %d", document["schema_version"], CurrentSchemaVersion)
+	}
+	if string(document["next_id"]) != "3" {
+		t.Errorf("next_id = %s, want 3", document["next_id"])
+	}
+	if string(document["created_at"]) != `"2025-01-01T00:00:00Z"` {
+		t.Errorf("created_at = %s, want the creation date of the oldest task", document["created_at"])
+	}
+}
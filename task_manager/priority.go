@@ -0,0 +1,66 @@
+package task_manager
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/TaskTrackerCLI/structures"
+)
+
+// Уровни приоритета тасков
+const (
+	PriorityLow      = "LOW"
+	PriorityMedium   = "MEDIUM"
+	PriorityHigh     = "HIGH"
+	PriorityCritical = "CRITICAL"
+)
+
+// DefaultPriority - приоритет новых тасков и тасков, созданных до появления приоритетов
+const DefaultPriority = PriorityMedium
+
+// Priorities - все уровни приоритета от низшего к высшему
+var Priorities = []string{PriorityLow, PriorityMedium, PriorityHigh, PriorityCritical}
+
+// ParsePriority - приводит приоритет к каноническому виду, регистр не важен
+func ParsePriority(value string) (string, error) {
+	priority := strings.ToUpper(strings.TrimSpace(value))
+	for _, known := range Priorities {
+		if priority == known {
+			return priority, nil
+		}
+	}
+	return "", fmt.Errorf("invalid priority %q, use one of %s", value, strings.Join(Priorities, ", "))
+}
+
+// priorityRank - место приоритета в Priorities, неизвестный или пустой считается DefaultPriority
+func priorityRank(priority string) int {
+	for rank, known := range Priorities {
+		if priority == known {
+			return rank
+		}
+	}
+	return priorityRank(DefaultPriority)
+}
+
+// sortTasks - сортирует таски по убыванию приоритета, при равном приоритете по id
+func sortTasks(tasks []structures.Task) {
+	sort.SliceStable(tasks, func(i, j int) bool {
+		first, second := priorityRank(tasks[i].TaskPriority), priorityRank(tasks[j].TaskPriority)
+		if first != second {
+			return first > second
+		}
+		return tasks[i].TaskId < tasks[j].TaskId
+	})
+}
+
+// FilterByPriority - оставляет таски с приоритетом priority
+func FilterByPriority(tasks []structures.Task, priority string) []structures.Task {
+	result := make([]structures.Task, 0, len(tasks))
+	for _, task := range tasks {
+		if priorityRank(task.TaskPriority) == priorityRank(priority) {
+			result = append(result, task)
+		}
+	}
+	return result
+}
@@ -0,0 +1,61 @@
+package task_manager
+
+import (
+	"testing"
+)
+
+// TestPriority - проверяет задание приоритета при создании и обновлении, фильтр и сортировку по приоритету.
+func TestPriority(t *testing.T) {
+	tm, err := NewTaskManagerWithStore(NewMemoryStore())
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		priority string
+		wantErr  bool
+	}{
+		{name: "Nice to have", priority: "low"},
+		{name: "Default", priority: ""},
+		{name: "Incident follow-up", priority: "Critical"},
+		{name: "Release", priority: "HIGH"},
+		{name: "Invalid", priority: "urgent", wantErr: true},
+	}
+	for _, tt := range tests {
+		values := map[string]string{}
+		if tt.priority != "" {
+			values["task_priority"] = tt.priority
+		}
+		_, err := tm.AddTaskWithValues(tt.name, "", values)
+		if (err != nil) != tt.wantErr {
+			t.Fatalf("AddTaskWithValues(%q) error = %v, wantErr %v", tt.priority, err, tt.wantErr)
+		}
+	}
+
+	tasks, err := tm.ListAllTasks()
+	if err != nil {
+		t.Fatalf("ListAllTasks() error = %v", err)
+	}
+	wantOrder := []string{"Incident follow-up", "Release", "Default", "Nice to have"}
+	if len(tasks) != len(wantOrder) {
+		t.Fatalf("got %d tasks, want %d", len(tasks), len(wantOrder))
+	}
+	for i, task := range tasks {
+		if task.TaskName != wantOrder[i] {
+			t.Errorf("task[%d] = %s (%s), want %s", i, task.TaskName, task.TaskPriority, wantOrder[i])
+		}
+	}
+
+	if _, err := tm.UpdateTask(1, map[string]string{"task_priority": "critical"}); err != nil {
+		t.Fatalf("UpdateTask() error = %v", err)
+	}
+	if _, err := tm.UpdateTask(1, map[string]string{"task_priority": "P0"}); err == nil {
+		t.Errorf("UpdateTask() with invalid priority error = nil")
+	}
+	tasks, _ = tm.ListAllTasks()
+	critical := FilterByPriority(tasks, PriorityCritical)
+	if len(critical) != 2 || critical[0].TaskId != 1 || critical[1].TaskId != 3 {
+		t.Errorf("FilterByPriority(CRITICAL) = %+v, want tasks 1 and 3", critical)
+	}
+}
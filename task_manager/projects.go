@@ -0,0 +1,306 @@
+package task_manager
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/TaskTrackerCLI/structures"
+)
+
+// projectsKey - ключ служебного значения в Store, под которым хранятся проекты
+const projectsKey = "projects"
+
+// ErrProjectNotEmpty - у удаляемого проекта есть таски, а решения, что с ними делать, нет
+var ErrProjectNotEmpty = errors.New("project has tasks: move them to another project or delete them")
+
+// projectRegistry - все проекты и счетчик их id, id удаленных проектов повторно не выдаются
+type projectRegistry struct {
+	NextId   int                  `json:"next_id"`
+	Projects []structures.Project `json:"projects"`
+}
+
+func loadProjects(store Store) (projectRegistry, error) {
+	registry := projectRegistry{NextId: 1}
+	raw, ok, err := store.Meta(projectsKey)
+	if err != nil || !ok {
+		return registry, err
+	}
+	if err := json.Unmarshal(raw, &registry); err != nil {
+		return registry, fmt.Errorf("failed to read projects: %w", err)
+	}
+	return registry, nil
+}
+
+func saveProjects(store Store, registry projectRegistry) error {
+	raw, err := json.Marshal(registry)
+	if err != nil {
+		return fmt.Errorf("failed to write projects: %w", err)
+	}
+	return store.SetMeta(projectsKey, raw)
+}
+
+// find - индекс проекта с именем name без учета регистра, -1 если такого нет
+func (registry projectRegistry) find(name string) int {
+	for i, project := range registry.Projects {
+		if strings.EqualFold(project.ProjectName, strings.TrimSpace(name)) {
+			return i
+		}
+	}
+	return -1
+}
+
+// has - есть ли проект с id projectId
+func (registry projectRegistry) has(projectId int) bool {
+	for _, project := range registry.Projects {
+		if project.ProjectId == projectId {
+			return true
+		}
+	}
+	return false
+}
+
+// resolveProject - id проекта по имени для task_project. Пустое имя или none - без проекта
+func resolveProject(store Store, name string) (int, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.EqualFold(name, "none") {
+		return 0, nil
+	}
+	registry, err := loadProjects(store)
+	if err != nil {
+		return 0, err
+	}
+	index := registry.find(name)
+	if index < 0 {
+		return 0, fmt.Errorf("project %q not found", name)
+	}
+	return registry.Projects[index].ProjectId, nil
+}
+
+func validateProjectName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", fmt.Errorf("project name must not be empty")
+	}
+	if strings.EqualFold(name, "none") {
+		return "", fmt.Errorf("project name %q is reserved", name)
+	}
+	return name, nil
+}
+
+// CreateProject - создает проект с уникальным (без учета регистра) именем
+func (taskManager *TaskManager) CreateProject(name string) (structures.Project, error) {
+	name, err := validateProjectName(name)
+	if err != nil {
+		return structures.Project{}, err
+	}
+	var project structures.Project
+	err = taskManager.mutate(OperationProject, func(store Store) error {
+		registry, err := loadProjects(store)
+		if err != nil {
+			return err
+		}
+		if registry.find(name) >= 0 {
+			return fmt.Errorf("project %q already exists", name)
+		}
+		project = structures.Project{
+			ProjectId:        registry.NextId,
+			ProjectName:      name,
+			ProjectCreatedAt: time.Now().Format(time.RFC3339),
+		}
+		registry.NextId++
+		registry.Projects = append(registry.Projects, project)
+		return saveProjects(store, registry)
+	})
+	if err != nil {
+		return structures.Project{}, fmt.Errorf("failed to create project: %w", err)
+	}
+	return project, nil
+}
+
+// FindProject - проект по имени без учета регистра
+func (taskManager *TaskManager) FindProject(name string) (structures.Project, bool, error) {
+	taskManager.mu.RLock()
+	defer taskManager.mu.RUnlock()
+	registry, err := loadProjects(taskManager.store)
+	if err != nil {
+		return structures.Project{}, false, err
+	}
+	index := registry.find(name)
+	if index < 0 {
+		return structures.Project{}, false, nil
+	}
+	return registry.Projects[index], true, nil
+}
+
+// RenameProject - переименовывает проект. false, если проекта name нет
+func (taskManager *TaskManager) RenameProject(name, newName string) (bool, error) {
+	newName, err := validateProjectName(newName)
+	if err != nil {
+		return false, err
+	}
+	var found bool
+	err = taskManager.mutate(OperationProject, func(store Store) error {
+		registry, err := loadProjects(store)
+		if err != nil {
+			return err
+		}
+		index := registry.find(name)
+		if index < 0 {
+			return nil
+		}
+		found = true
+		if other := registry.find(newName); other >= 0 && other != index {
+			return fmt.Errorf("project %q already exists", newName)
+		}
+		registry.Projects[index].ProjectName = newName
+		return saveProjects(store, registry)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to rename project: %w", err)
+	}
+	return found, nil
+}
+
+// DeleteProject - удаляет проект name. Таски проекта переносятся в проект moveTo (none - без проекта),
+// а если moveTo пустой и deleteTasks - в корзину вместе с подзадачами. Если у проекта есть таски и решения о них нет,
+// возвращается ErrProjectNotEmpty. Архив не читается: архивные таски сохраняют id проекта и
+// отвязываются от него, если их вернут из архива (см. UnarchiveTask)
+func (taskManager *TaskManager) DeleteProject(name, moveTo string, deleteTasks bool) (bool, error) {
+	if moveTo != "" && deleteTasks {
+		return false, fmt.Errorf("choose either to move or to delete the project tasks")
+	}
+	var found bool
+	err := taskManager.mutate(OperationProject, func(store Store) error {
+		registry, err := loadProjects(store)
+		if err != nil {
+			return err
+		}
+		index := registry.find(name)
+		if index < 0 {
+			return nil
+		}
+		found = true
+		project := registry.Projects[index]
+
+		target := 0
+		if moveTo != "" {
+			if target, err = resolveProject(store, moveTo); err != nil {
+				return err
+			}
+			if target == project.ProjectId {
+				return fmt.Errorf("cannot move tasks of project %q into itself", project.ProjectName)
+			}
+		}
+
+		tasks, err := listUnarchived(store)
+		if err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			if task.TaskProjectId != project.ProjectId {
+				continue
+			}
+			// таск мог уже попасть в корзину вместе с родителем из этого же проекта
+			if task, _, err = store.Get(task.TaskId); err != nil {
+				return err
+			}
+			switch {
+			case !isActive(task):
+				task.TaskProjectId = 0
+			case moveTo != "":
+				task.TaskProjectId = target
+			case deleteTasks:
+				// как и DeleteTask, уносит в корзину и подзадачи, даже если они в другом проекте
+				task.TaskProjectId = 0
+				if err := moveToTrash(store, task); err != nil {
+					return err
+				}
+				continue
+			default:
+				return ErrProjectNotEmpty
+			}
+			if err := store.Put(task); err != nil {
+				return err
+			}
+		}
+
+		registry.Projects = append(registry.Projects[:index], registry.Projects[index+1:]...)
+		return saveProjects(store, registry)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to delete project: %w", err)
+	}
+	return found, nil
+}
+
+// ProjectProgress - проект и количество его тасков рабочего набора по статусам
+type ProjectProgress struct {
+	Project structures.Project
+	Todo    int
+	// InProgress - таски во всех статусах рабочего процесса между TODO и DONE
+	InProgress int
+	Done       int
+}
+
+// Percent - доля выполненных тасков в процентах
+func (progress ProjectProgress) Percent() int {
+	total := progress.Todo + progress.InProgress + progress.Done
+	if total == 0 {
+		return 0
+	}
+	return progress.Done * 100 / total
+}
+
+// ListProjects - все проекты с прогрессом, по имени
+func (taskManager *TaskManager) ListProjects() ([]ProjectProgress, error) {
+	taskManager.mu.RLock()
+	defer taskManager.mu.RUnlock()
+
+	registry, err := loadProjects(taskManager.store)
+	if err != nil {
+		return nil, err
+	}
+	tasks, err := listActive(taskManager.store)
+	if err != nil {
+		return nil, err
+	}
+	progress := make(map[int]*ProjectProgress, len(registry.Projects))
+	result := make([]ProjectProgress, len(registry.Projects))
+	for i, project := range registry.Projects {
+		result[i].Project = project
+		progress[project.ProjectId] = &result[i]
+	}
+	for _, task := range tasks {
+		counts, ok := progress[task.TaskProjectId]
+		if !ok {
+			continue
+		}
+		switch {
+		case task.TaskStatus == StatusDone:
+			counts.Done++
+		case task.TaskStatus == StatusTodo:
+			counts.Todo++
+		default:
+			counts.InProgress++
+		}
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return strings.ToLower(result[i].Project.ProjectName) < strings.ToLower(result[j].Project.ProjectName)
+	})
+	return result, nil
+}
+
+// FilterByProject - оставляет таски проекта projectId, 0 - таски без проекта
+func FilterByProject(tasks []structures.Task, projectId int) []structures.Task {
+	result := make([]structures.Task, 0, len(tasks))
+	for _, task := range tasks {
+		if task.TaskProjectId == projectId {
+			result = append(result, task)
+		}
+	}
+	return result
+}
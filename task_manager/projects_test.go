@@ -0,0 +1,135 @@
+package task_manager
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+)
+
+// TestProjects - проверяет создание, переименование и удаление проектов, привязку тасков и прогресс по проектам.
+func TestProjects(t *testing.T) {
+	tm, err := NewTaskManagerWithStore(NewMemoryStore())
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+
+	backend, err := tm.CreateProject("Backend")
+	if err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+	if _, err := tm.CreateProject("Website"); err != nil {
+		t.Fatalf("CreateProject() error = %v", err)
+	}
+	if _, err := tm.CreateProject("backend"); err == nil {
+		t.Errorf("CreateProject() with a duplicate name error = nil")
+	}
+
+	for _, project := range []string{"backend", "Backend", "Website", ""} {
+		if _, err := tm.AddTaskWithValues("task", "", map[string]string{"task_project": project}); err != nil {
+			t.Fatalf("AddTaskWithValues(project %q) error = %v", project, err)
+		}
+	}
+	if _, err := tm.AddTaskWithValues("task", "", map[string]string{"task_project": "Mobile"}); err == nil {
+		t.Errorf("AddTaskWithValues() with an unknown project error = nil")
+	}
+	if _, err := tm.MarkTaskAsDone(1); err != nil {
+		t.Fatalf("MarkTaskAsDone() error = %v", err)
+	}
+	if _, _, err := tm.MarkTaskAsInProgress(2); err != nil {
+		t.Fatalf("MarkTaskAsInProgress() error = %v", err)
+	}
+
+	tasks, _ := tm.ListAllTasks()
+	if got := FilterByProject(tasks, backend.ProjectId); len(got) != 2 {
+		t.Errorf("FilterByProject(backend) returned %d tasks, want 2", len(got))
+	}
+
+	progress, err := tm.ListProjects()
+	if err != nil {
+		t.Fatalf("ListProjects() error = %v", err)
+	}
+	if len(progress) != 2 || progress[0].Project.ProjectName != "Backend" {
+		t.Fatalf("ListProjects() = %+v", progress)
+	}
+	if got := progress[0]; got.Todo != 0 || got.InProgress != 1 || got.Done != 1 || got.Percent() != 50 {
+		t.Errorf("Backend progress = %+v (%d%%), want 0/1/1 (50%%)", got, got.Percent())
+	}
+
+	if ok, err := tm.RenameProject("website", "Web"); !ok || err != nil {
+		t.Fatalf("RenameProject() = %v, %v", ok, err)
+	}
+	if ok, err := tm.RenameProject("Web", "backend"); err == nil {
+		t.Errorf("RenameProject() to an existing name = %v, nil, want error", ok)
+	}
+
+	if _, err := tm.DeleteProject("Backend", "", false); !errors.Is(err, ErrProjectNotEmpty) {
+		t.Fatalf("DeleteProject() without a decision error = %v, want ErrProjectNotEmpty", err)
+	}
+	if ok, err := tm.DeleteProject("Backend", "web", false); !ok || err != nil {
+		t.Fatalf("DeleteProject(move) = %v, %v", ok, err)
+	}
+	web, _, _ := tm.FindProject("web")
+	tasks, _ = tm.ListAllTasks()
+	if got := FilterByProject(tasks, web.ProjectId); len(got) != 3 {
+		t.Errorf("after moving, project Web has %d tasks, want 3", len(got))
+	}
+
+	if ok, err := tm.DeleteProject("Web", "", true); !ok || err != nil {
+		t.Fatalf("DeleteProject(delete tasks) = %v, %v", ok, err)
+	}
+	if tasks, _ := tm.ListAllTasks(); len(tasks) != 1 {
+		t.Errorf("after deleting project tasks %d tasks remain, want 1", len(tasks))
+	}
+
+	// удаление проекта вместе с тасками отменяется одним шагом, включая сам проект
+	if _, ok, err := tm.Undo(); !ok || err != nil {
+		t.Fatalf("Undo() = %v, %v", ok, err)
+	}
+	if _, ok, _ := tm.FindProject("Web"); !ok {
+		t.Errorf("Undo() did not restore the deleted project")
+	}
+	if tasks, _ := tm.ListAllTasks(); len(tasks) != 4 {
+		t.Errorf("after undo %d tasks are active, want 4", len(tasks))
+	}
+}
+
+// TestDeleteProjectTrashesSubtasks - удаление проекта вместе с тасками уносит в корзину и подзадачи
+// из других проектов, а восстановление родителя возвращает их.
+func TestDeleteProjectTrashesSubtasks(t *testing.T) {
+	tm, err := NewTaskManagerWithStore(NewMemoryStore())
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+	for _, name := range []string{"Backend", "Docs"} {
+		if _, err := tm.CreateProject(name); err != nil {
+			t.Fatalf("CreateProject(%q) error = %v", name, err)
+		}
+	}
+	parent, err := tm.AddTaskWithValues("release", "", map[string]string{"task_project": "backend"})
+	if err != nil {
+		t.Fatalf("AddTaskWithValues() error = %v", err)
+	}
+	parentID := strconv.Itoa(parent)
+	docs, _ := tm.AddTaskWithValues("changelog", "", map[string]string{"task_parent": parentID, "task_project": "docs"})
+	plain, _ := tm.AddTaskWithValues("tag", "", map[string]string{"task_parent": parentID})
+	backend, _ := tm.AddTaskWithValues("migrate", "", map[string]string{"task_parent": parentID, "task_project": "backend"})
+
+	if ok, err := tm.DeleteProject("Backend", "", true); !ok || err != nil {
+		t.Fatalf("DeleteProject(delete tasks) = %v, %v", ok, err)
+	}
+	if tasks, _ := tm.ListAllTasks(); len(tasks) != 0 {
+		t.Errorf("after deleting the project %d tasks are active, want 0: %+v", len(tasks), tasks)
+	}
+	if trash, _ := tm.ListTrash(); len(trash) != 4 {
+		t.Errorf("trash has %d tasks, want the parent with its 3 subtasks", len(trash))
+	}
+
+	if ok, err := tm.RestoreTask(parent); !ok || err != nil {
+		t.Fatalf("RestoreTask() = %v, %v", ok, err)
+	}
+	for _, id := range []int{docs, plain, backend} {
+		if _, ok, _ := tm.GetTask(id); !ok {
+			t.Errorf("subtask %d was not restored with its parent", id)
+		}
+	}
+}
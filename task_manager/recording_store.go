@@ -0,0 +1,133 @@
+package task_manager
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/TaskTrackerCLI/structures"
+)
+
+// recordingStore - обертка над Store на время одной операции: запоминает состояние каждого
+// затронутого таска до первого изменения и после последнего
+type recordingStore struct {
+	Store
+	changes     []Change
+	index       map[int]int
+	metaChanged bool
+	meta        []MetaChange
+	metaIndex   map[string]int
+}
+
+func newRecordingStore(store Store) *recordingStore {
+	return &recordingStore{Store: store, index: make(map[int]int), metaIndex: make(map[string]int)}
+}
+
+func (store *recordingStore) Put(task structures.Task) error {
+	if err := store.track(task.TaskId); err != nil {
+		return err
+	}
+	if err := store.Store.Put(task); err != nil {
+		return err
+	}
+	after := task
+	store.changes[store.index[task.TaskId]].After = &after
+	return nil
+}
+
+func (store *recordingStore) Delete(id int) error {
+	if err := store.track(id); err != nil {
+		return err
+	}
+	if err := store.Store.Delete(id); err != nil {
+		return err
+	}
+	store.changes[store.index[id]].After = nil
+	return nil
+}
+
+// ListActive - рабочий набор обернутого хранилища. Только читает, поэтому ничего не запоминает
+func (store *recordingStore) ListActive() ([]structures.Task, error) {
+	return listActive(store.Store)
+}
+
+// ListUnarchived - таски без архива из обернутого хранилища
+func (store *recordingStore) ListUnarchived() ([]structures.Task, error) {
+	return listUnarchived(store.Store)
+}
+
+// ListArchivedSince - архивные таски из обернутого хранилища, убранные в архив не раньше since
+func (store *recordingStore) ListArchivedSince(since time.Time) ([]structures.Task, error) {
+	return listArchivedSince(store.Store, since)
+}
+
+// SetMeta - запоминает исходное значение ключа при первом изменении. История отмены
+// сама себя не отслеживает
+func (store *recordingStore) SetMeta(key string, value json.RawMessage) error {
+	if _, ok := store.metaIndex[key]; !ok && !isUndoKey(key) {
+		before, _, err := store.Store.Meta(key)
+		if err != nil {
+			return err
+		}
+		store.metaIndex[key] = len(store.meta)
+		store.meta = append(store.meta, MetaChange{Key: key, Before: before})
+	}
+	if err := store.Store.SetMeta(key, value); err != nil {
+		return err
+	}
+	if index, ok := store.metaIndex[key]; ok {
+		store.meta[index].After = value
+	}
+	store.metaChanged = true
+	return nil
+}
+
+// track - при первом обращении к таску запоминает его исходное состояние
+func (store *recordingStore) track(id int) error {
+	if _, ok := store.index[id]; ok {
+		return nil
+	}
+	change := Change{TaskId: id}
+	before, ok, err := store.Store.Get(id)
+	if err != nil {
+		return err
+	}
+	if ok {
+		change.Before = &before
+		change.After = &before
+	}
+	store.index[id] = len(store.changes)
+	store.changes = append(store.changes, change)
+	return nil
+}
+
+// taskChanges - изменения тасков без тех, что вернули таск в исходное состояние или создали и тут же удалили
+func (store *recordingStore) taskChanges() []Change {
+	changes := make([]Change, 0, len(store.changes))
+	for _, change := range store.changes {
+		if change.Before == nil && change.After == nil {
+			continue
+		}
+		if change.Before != nil && change.After != nil && sameTask(*change.Before, *change.After) {
+			continue
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// metaChanges - изменения служебных значений без тех, что вернулись к исходному
+func (store *recordingStore) metaChanges() []MetaChange {
+	changes := make([]MetaChange, 0, len(store.meta))
+	for _, change := range store.meta {
+		if string(change.Before) != string(change.After) {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+func sameTask(a, b structures.Task) bool {
+	first, errA := json.Marshal(a)
+	second, errB := json.Marshal(b)
+	return errA == nil && errB == nil && string(first) == string(second)
+}
@@ -0,0 +1,102 @@
+package task_manager
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/TaskTrackerCLI/dates"
+	"github.com/TaskTrackerCLI/structures"
+)
+
+// maxSkippedOccurrences - сколько пропущенных повторов можно перешагнуть в поисках даты не в прошлом
+const maxSkippedOccurrences = 1000
+
+// parseRecurrence - правило повторения в каноническом виде и первый подходящий под него день начиная
+// с сегодняшнего - срок для таска, у которого его еще нет. Правило закрепляется за сроком due, а без
+// срока - за этим первым днем (см. dates.Rule.AnchoredTo). Пустая строка или none - таск не повторяется
+func parseRecurrence(value, due string, now time.Time) (string, string, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || strings.EqualFold(value, "none") {
+		return "", "", nil
+	}
+	rule, err := dates.ParseRule(value)
+	if err != nil {
+		return "", "", err
+	}
+	first, ok := rule.First(now)
+	if !ok {
+		return "", "", fmt.Errorf("recurrence %q never happens", value)
+	}
+	anchor := first
+	if day, err := dates.ParseStored(due, now.Location()); due != "" && err == nil {
+		anchor = day
+	}
+	return rule.AnchoredTo(anchor).String(), dates.Format(first), nil
+}
+
+// scheduleNextOccurrence - для выполненного повторяющегося таска создает следующий экземпляр серии
+// со сроком по правилу и неотмеченным чек-листом и передает ему правило: серия продолжается только от последнего экземпляра.
+// Отсчет идет от срока таска, а без срока - от сегодняшнего дня; повторы, которые уже в прошлом, пропускаются.
+// Возвращает id созданного экземпляра, 0 если серия закончилась
+func scheduleNextOccurrence(store Store, task *structures.Task, now time.Time) (int, error) {
+	if task.TaskRecurrence == "" {
+		return 0, nil
+	}
+	rule, err := dates.ParseRule(task.TaskRecurrence)
+	if err != nil {
+		return 0, err
+	}
+	task.TaskRecurrence = ""
+
+	today := dates.StartOfDay(now)
+	base, ok := DueDate(*task, now.Location())
+	if !ok {
+		base = today
+	}
+	// серии, заведенные до закрепления дня месяца, закрепляются за текущим сроком
+	rule = rule.AnchoredTo(base)
+	recurrence := rule.String()
+	next, ok := rule.Next(base)
+	for skipped := 0; ok && next.Before(today) && skipped < maxSkippedOccurrences; skipped++ {
+		next, ok = rule.Next(next)
+	}
+	if !ok {
+		return 0, nil
+	}
+
+	id, err := store.NextID()
+	if err != nil {
+		return 0, err
+	}
+	var checklist []structures.ChecklistItem
+	for _, item := range task.TaskChecklist {
+		checklist = append(checklist, structures.ChecklistItem{Text: item.Text})
+	}
+	return id, store.Put(structures.Task{
+		TaskId:          id,
+		TaskName:        task.TaskName,
+		TaskDescription: task.TaskDescription,
+		TaskStatus:      StatusTodo,
+		TaskPriority:    task.TaskPriority,
+		TaskDueDate:     dates.Format(next),
+		TaskRecurrence:  recurrence,
+		TaskEstimate:    task.TaskEstimate,
+		TaskParentId:    task.TaskParentId,
+		TaskProjectId:   task.TaskProjectId,
+		TaskTags:        append([]string(nil), task.TaskTags...),
+		TaskChecklist:   checklist,
+		TaskCreatedAt:   now.Format(time.RFC3339),
+	})
+}
+
+// FilterRecurring - оставляет повторяющиеся таски, то есть текущие экземпляры серий
+func FilterRecurring(tasks []structures.Task) []structures.Task {
+	result := make([]structures.Task, 0, len(tasks))
+	for _, task := range tasks {
+		if task.TaskRecurrence != "" {
+			result = append(result, task)
+		}
+	}
+	return result
+}
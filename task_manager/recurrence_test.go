@@ -0,0 +1,160 @@
+package task_manager
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/TaskTrackerCLI/dates"
+	"github.com/TaskTrackerCLI/structures"
+)
+
+// TestRecurringTasks - проверяет, что выполнение повторяющегося таска создает следующий экземпляр серии,
+// пропуская прошедшие даты, что отмена убирает его и что серию можно остановить.
+func TestRecurringTasks(t *testing.T) {
+	tm, err := NewTaskManagerWithStore(NewMemoryStore())
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+	today := dates.StartOfDay(time.Now())
+	listAll := func() []structures.Task {
+		t.Helper()
+		tasks, err := tm.ListAllTasks()
+		if err != nil {
+			t.Fatalf("ListAllTasks() error = %v", err)
+		}
+		return tasks
+	}
+
+	if _, err := tm.AddTaskWithValues("handover", "", map[string]string{"task_recur": "yearly"}); err == nil {
+		t.Errorf("AddTaskWithValues() with an invalid rule error = nil")
+	}
+	id, err := tm.AddTaskWithValues("handover", "on-call", map[string]string{
+		"task_recur":    "every 2w",
+		"task_due":      dates.Format(today.AddDate(0, 0, 3)),
+		"task_priority": "high",
+		"task_tags":     "ops",
+	})
+	if err != nil {
+		t.Fatalf("AddTaskWithValues() error = %v", err)
+	}
+	if task, _, _ := tm.GetTask(id); task.TaskRecurrence != "every:2w" {
+		t.Errorf("TaskRecurrence = %q, want the canonical every:2w", task.TaskRecurrence)
+	}
+
+	_, change, err := tm.SetStatus(id, StatusDone)
+	if err != nil {
+		t.Fatalf("SetStatus(DONE) error = %v", err)
+	}
+	recurring := FilterRecurring(listAll())
+	if len(recurring) != 1 {
+		t.Fatalf("after completing an occurrence %d recurring tasks, want 1", len(recurring))
+	}
+	next := recurring[0]
+	if change.NextOccurrence != next.TaskId {
+		t.Errorf("SetStatus(DONE) NextOccurrence = %d, want %d", change.NextOccurrence, next.TaskId)
+	}
+	if next.TaskId == id || next.TaskStatus != StatusTodo || next.TaskPriority != PriorityHigh || len(next.TaskTags) != 1 {
+		t.Errorf("next occurrence = %+v", next)
+	}
+	if want := dates.Format(today.AddDate(0, 0, 17)); next.TaskDueDate != want {
+		t.Errorf("next occurrence due %s, want %s", next.TaskDueDate, want)
+	}
+	if done, _, _ := tm.GetTask(id); done.TaskRecurrence != "" {
+		t.Errorf("completed occurrence kept the rule %q", done.TaskRecurrence)
+	}
+
+	if _, _, err := tm.Undo(); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if tasks := listAll(); len(tasks) != 1 || tasks[0].TaskRecurrence == "" {
+		t.Errorf("Undo() must remove the next occurrence and give the rule back: %+v", tasks)
+	}
+
+	// срок давно прошел: следующий экземпляр не должен оказаться в прошлом
+	overdue, err := tm.AddTaskWithValues("audit", "", map[string]string{"task_recur": "weekly", "task_due": dates.Format(today.AddDate(0, 0, -30))})
+	if err != nil {
+		t.Fatalf("AddTaskWithValues() error = %v", err)
+	}
+	if _, err := tm.MarkTaskAsDone(overdue); err != nil {
+		t.Fatalf("MarkTaskAsDone() error = %v", err)
+	}
+	for _, task := range FilterRecurring(listAll()) {
+		if task.TaskName != "audit" {
+			continue
+		}
+		if want := dates.Format(today.AddDate(0, 0, 5)); task.TaskDueDate != want {
+			t.Errorf("next occurrence of an overdue task due %s, want %s", task.TaskDueDate, want)
+		}
+		if _, err := tm.UpdateTask(task.TaskId, map[string]string{"task_recur": "none"}); err != nil {
+			t.Fatalf("UpdateTask(stop series) error = %v", err)
+		}
+		if _, err := tm.MarkTaskAsDone(task.TaskId); err != nil {
+			t.Fatalf("MarkTaskAsDone() error = %v", err)
+		}
+	}
+	for _, task := range listAll() {
+		if task.TaskName == "audit" && task.TaskStatus != StatusDone {
+			t.Errorf("stopped series spawned another occurrence: %+v", task)
+		}
+	}
+
+	// monthly со сроком 31-го не сползает на 28-е после февраля
+	year := today.Year() + 1
+	rent, err := tm.AddTaskWithValues("rent", "", map[string]string{"task_recur": "monthly", "task_due": fmt.Sprintf("%d-01-31", year)})
+	if err != nil {
+		t.Fatalf("AddTaskWithValues() error = %v", err)
+	}
+	if task, _, _ := tm.GetTask(rent); task.TaskRecurrence != "monthly:31" {
+		t.Errorf("TaskRecurrence = %q, want monthly anchored to monthly:31", task.TaskRecurrence)
+	}
+	for _, want := range []string{"02-28", "03-31", "04-30"} {
+		if _, err := tm.MarkTaskAsDone(rent); err != nil {
+			t.Fatalf("MarkTaskAsDone() error = %v", err)
+		}
+		rent = 0
+		for _, task := range FilterRecurring(listAll()) {
+			if task.TaskName == "rent" {
+				rent = task.TaskId
+				if task.TaskDueDate != fmt.Sprintf("%d-%s", year, want) {
+					t.Errorf("next rent due %s, want %d-%s", task.TaskDueDate, year, want)
+				}
+			}
+		}
+		if rent == 0 {
+			t.Fatalf("no next occurrence of rent")
+		}
+	}
+
+	report, err := tm.AddTaskWithValues("report", "", map[string]string{"task_recur": "daily"})
+	if err != nil {
+		t.Fatalf("AddTaskWithValues() error = %v", err)
+	}
+	if task, _, _ := tm.GetTask(report); task.TaskDueDate != dates.Format(today) {
+		t.Errorf("recurring task without a due date got due %q, want today", task.TaskDueDate)
+	}
+}
+
+// TestRecurrenceWithoutNextDate - если у правила больше нет дат, выполнение таска не создает экземпляр
+// и SetStatus не сообщает о нем.
+func TestRecurrenceWithoutNextDate(t *testing.T) {
+	store := NewMemoryStore()
+	// 30 февраля не бывает; правило с такой датой могло попасть в файл только ручной правкой
+	if err := store.Put(structures.Task{TaskId: 1, TaskName: "leap", TaskStatus: StatusTodo, TaskRecurrence: `cron:"0 0 30 2 *"`}); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	tm, err := NewTaskManagerWithStore(store)
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+	ok, change, err := tm.SetStatus(1, StatusDone)
+	if !ok || err != nil {
+		t.Fatalf("SetStatus(DONE) = %v, %v", ok, err)
+	}
+	if change.NextOccurrence != 0 {
+		t.Errorf("SetStatus(DONE) NextOccurrence = %d, want none", change.NextOccurrence)
+	}
+	if tasks, _ := tm.ListAllTasks(); len(tasks) != 1 {
+		t.Errorf("got %d tasks, want only the completed one", len(tasks))
+	}
+}
@@ -0,0 +1,355 @@
+package task_manager
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/TaskTrackerCLI/structures"
+	_ "modernc.org/sqlite"
+)
+
+// sqliteMigrations - миграции схемы базы, индекс в срезе + 1 совпадает с PRAGMA user_version после применения.
+// Полный таск хранится в колонке data, остальные колонки нужны для индексов и фильтрации в SQL
+var sqliteMigrations = []string{
+	`CREATE TABLE tasks (
+		id          INTEGER PRIMARY KEY,
+		name        TEXT NOT NULL,
+		description TEXT NOT NULL,
+		status      TEXT NOT NULL,
+		created_at  TEXT NOT NULL,
+		updated_at  TEXT NOT NULL,
+		search_text TEXT NOT NULL,
+		data        TEXT NOT NULL
+	);
+	CREATE INDEX idx_tasks_status ON tasks(status);
+	CREATE INDEX idx_tasks_created_at ON tasks(created_at);
+	CREATE INDEX idx_tasks_updated_at ON tasks(updated_at);`,
+	`CREATE TABLE meta (
+		key   TEXT PRIMARY KEY,
+		value INTEGER NOT NULL
+	);
+	INSERT INTO meta (key, value) SELECT 'next_id', COALESCE(MAX(id), 0) + 1 FROM tasks;`,
+	`CREATE TABLE metadata (
+		key   TEXT PRIMARY KEY,
+		value TEXT NOT NULL
+	);`,
+	`ALTER TABLE tasks ADD COLUMN deleted_at TEXT NOT NULL DEFAULT '';
+	CREATE INDEX idx_tasks_deleted_at ON tasks(deleted_at);`,
+	`ALTER TABLE tasks ADD COLUMN archived_at TEXT NOT NULL DEFAULT '';
+	CREATE INDEX idx_tasks_active_status ON tasks(status) WHERE deleted_at = '' AND archived_at = '';`,
+	`UPDATE tasks SET data = json_set(data, '$.task_priority', 'MEDIUM') WHERE json_extract(data, '$.task_priority') IS NULL;`,
+}
+
+// SQLiteStore - хранилище тасков во встроенной базе SQLite (драйвер без cgo).
+// Изменения между Load и Save выполняются в одной транзакции: Save ее фиксирует, Load откатывает
+type SQLiteStore struct {
+	FilePath string
+	db       *sql.DB
+	tx       *sql.Tx
+}
+
+// sqlConn - общие методы *sql.DB и *sql.Tx
+type sqlConn interface {
+	Exec(query string, args ...any) (sql.Result, error)
+	Query(query string, args ...any) (*sql.Rows, error)
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+func NewSQLiteStore(filePath string) *SQLiteStore {
+	return &SQLiteStore{FilePath: filePath}
+}
+
+// Load - открывает базу при первом вызове и применяет недостающие миграции.
+// Незафиксированные изменения откатываются
+func (store *SQLiteStore) Load() error {
+	if store.db != nil {
+		return store.rollback()
+	}
+	db, err := sql.Open("sqlite", "file:"+store.FilePath+"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)")
+	if err != nil {
+		return fmt.Errorf("failed to open tasks database: %w", err)
+	}
+	if err := migrateSQLite(db); err != nil {
+		_ = db.Close()
+		return err
+	}
+	store.db = db
+	return nil
+}
+
+// migrateSQLite - применяет миграции под BEGIN IMMEDIATE, чтобы несколько процессов,
+// одновременно открывших новую базу, не пытались создать схему дважды
+func migrateSQLite(db *sql.DB) (err error) {
+	ctx := context.Background()
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, "BEGIN IMMEDIATE"); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+	defer func() {
+		if err != nil {
+			_, _ = conn.ExecContext(ctx, "ROLLBACK")
+		}
+	}()
+
+	var version int
+	if err := conn.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return fmt.Errorf("failed to read database schema version: %w", err)
+	}
+	for ; version < len(sqliteMigrations); version++ {
+		if _, err := conn.ExecContext(ctx, sqliteMigrations[version]); err != nil {
+			return fmt.Errorf("failed to migrate database to version %d: %w", version+1, err)
+		}
+		if _, err := conn.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", version+1)); err != nil {
+			return fmt.Errorf("failed to migrate database to version %d: %w", version+1, err)
+		}
+	}
+	if _, err := conn.ExecContext(ctx, "COMMIT"); err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+	return nil
+}
+
+// Lock - блокирует базу от изменений другими процессами TaskTracker на время цикла загрузка-изменение-сохранение
+func (store *SQLiteStore) Lock(timeout time.Duration) (func() error, error) {
+	return lockFile(store.FilePath+".lock", timeout)
+}
+
+// Save - фиксирует транзакцию с изменениями, сделанными после Load
+func (store *SQLiteStore) Save() error {
+	if store.tx == nil {
+		return nil
+	}
+	err := store.tx.Commit()
+	store.tx = nil
+	if err != nil {
+		return fmt.Errorf("failed to commit tasks: %w", err)
+	}
+	return nil
+}
+
+// Close - откатывает незафиксированные изменения и закрывает соединение с базой
+func (store *SQLiteStore) Close() error {
+	if store.db == nil {
+		return nil
+	}
+	rollbackErr := store.rollback()
+	err := store.db.Close()
+	store.db = nil
+	if err == nil {
+		err = rollbackErr
+	}
+	return err
+}
+
+func (store *SQLiteStore) rollback() error {
+	if store.tx == nil {
+		return nil
+	}
+	err := store.tx.Rollback()
+	store.tx = nil
+	if err != nil {
+		return fmt.Errorf("failed to roll back tasks: %w", err)
+	}
+	return nil
+}
+
+// conn - текущая транзакция, если она открыта, иначе сама база
+func (store *SQLiteStore) conn() sqlConn {
+	if store.tx != nil {
+		return store.tx
+	}
+	return store.db
+}
+
+// write - возвращает транзакцию для изменений, открывая ее при первой записи
+func (store *SQLiteStore) write() (sqlConn, error) {
+	if store.tx == nil {
+		tx, err := store.db.Begin()
+		if err != nil {
+			return nil, fmt.Errorf("failed to begin transaction: %w", err)
+		}
+		store.tx = tx
+	}
+	return store.tx, nil
+}
+
+func (store *SQLiteStore) Get(id int) (structures.Task, bool, error) {
+	var data string
+	err := store.conn().QueryRow("SELECT data FROM tasks WHERE id = ?", id).Scan(&data)
+	if err == sql.ErrNoRows {
+		return structures.Task{}, false, nil
+	}
+	if err != nil {
+		return structures.Task{}, false, fmt.Errorf("failed to read task %d: %w", id, err)
+	}
+	var task structures.Task
+	if err := json.Unmarshal([]byte(data), &task); err != nil {
+		return structures.Task{}, false, fmt.Errorf("failed to unmarshal task %d: %w", id, err)
+	}
+	return task, true, nil
+}
+
+func (store *SQLiteStore) Put(task structures.Task) error {
+	data, err := json.Marshal(task)
+	if err != nil {
+		return fmt.Errorf("failed to marshal task %d: %w", task.TaskId, err)
+	}
+	conn, err := store.write()
+	if err != nil {
+		return err
+	}
+	_, err = conn.Exec(`INSERT INTO tasks (id, name, description, status, created_at, updated_at, deleted_at, archived_at, search_text, data)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			name = excluded.name,
+			description = excluded.description,
+			status = excluded.status,
+			created_at = excluded.created_at,
+			updated_at = excluded.updated_at,
+			deleted_at = excluded.deleted_at,
+			archived_at = excluded.archived_at,
+			search_text = excluded.search_text,
+			data = excluded.data`,
+		task.TaskId, task.TaskName, task.TaskDescription, task.TaskStatus,
+		task.TaskCreatedAt, task.TaskUpdatedAt, task.TaskDeletedAt, task.TaskArchivedAt, sqliteSearchText(task), string(data))
+	if err != nil {
+		return fmt.Errorf("failed to write task %d: %w", task.TaskId, err)
+	}
+	if _, err := conn.Exec("UPDATE meta SET value = ? WHERE key = 'next_id' AND value <= ?", task.TaskId+1, task.TaskId); err != nil {
+		return fmt.Errorf("failed to update id counter: %w", err)
+	}
+	return nil
+}
+
+func (store *SQLiteStore) Delete(id int) error {
+	conn, err := store.write()
+	if err != nil {
+		return err
+	}
+	if _, err := conn.Exec("DELETE FROM tasks WHERE id = ?", id); err != nil {
+		return fmt.Errorf("failed to delete task %d: %w", id, err)
+	}
+	return nil
+}
+
+// NextID - выдает id из счетчика в таблице meta в рамках текущей транзакции
+func (store *SQLiteStore) NextID() (int, error) {
+	conn, err := store.write()
+	if err != nil {
+		return 0, err
+	}
+	var id int
+	if err := conn.QueryRow("UPDATE meta SET value = value + 1 WHERE key = 'next_id' RETURNING value - 1").Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to allocate task id: %w", err)
+	}
+	return id, nil
+}
+
+func (store *SQLiteStore) Meta(key string) (json.RawMessage, bool, error) {
+	var value string
+	err := store.conn().QueryRow("SELECT value FROM metadata WHERE key = ?", key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to read %s: %w", key, err)
+	}
+	return json.RawMessage(value), true, nil
+}
+
+func (store *SQLiteStore) SetMeta(key string, value json.RawMessage) error {
+	conn, err := store.write()
+	if err != nil {
+		return err
+	}
+	if value == nil {
+		_, err = conn.Exec("DELETE FROM metadata WHERE key = ?", key)
+	} else {
+		_, err = conn.Exec("INSERT INTO metadata (key, value) VALUES (?, ?) ON CONFLICT(key) DO UPDATE SET value = excluded.value", key, string(value))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", key, err)
+	}
+	return nil
+}
+
+func (store *SQLiteStore) List() ([]structures.Task, error) {
+	return store.query("SELECT data FROM tasks ORDER BY id")
+}
+
+// ListActive - выбирает таски рабочего набора по частичному индексу idx_tasks_active_status,
+// не читая корзину и архив
+func (store *SQLiteStore) ListActive() ([]structures.Task, error) {
+	return store.query("SELECT data FROM tasks WHERE deleted_at = '' AND archived_at = '' ORDER BY id")
+}
+
+// ListUnarchived - выбирает таски рабочего набора и корзины
+func (store *SQLiteStore) ListUnarchived() ([]structures.Task, error) {
+	return store.query("SELECT data FROM tasks WHERE archived_at = '' ORDER BY id")
+}
+
+// ListArchivedSince - выбирает архивные таски, убранные в архив не раньше since. Время в archived_at
+// записано в местном поясе, поэтому сравнивается после разбора, а не строкой в SQL
+func (store *SQLiteStore) ListArchivedSince(since time.Time) ([]structures.Task, error) {
+	tasks, err := store.query("SELECT data FROM tasks WHERE archived_at != '' ORDER BY id")
+	if err != nil {
+		return nil, err
+	}
+	result := tasks[:0]
+	for _, task := range tasks {
+		if archivedSince(task.TaskArchivedAt, since) {
+			result = append(result, task)
+		}
+	}
+	return result, nil
+}
+
+// ListByStatus - выбирает таски рабочего набора со статусом status
+func (store *SQLiteStore) ListByStatus(status string) ([]structures.Task, error) {
+	return store.query("SELECT data FROM tasks WHERE status = ? AND deleted_at = '' AND archived_at = '' ORDER BY id", status)
+}
+
+// Search - ищет подстроку в имени и описании тасков рабочего набора без учета регистра
+func (store *SQLiteStore) Search(query string) ([]structures.Task, error) {
+	return store.query("SELECT data FROM tasks WHERE instr(search_text, ?) > 0 AND deleted_at = '' AND archived_at = '' ORDER BY id", strings.ToLower(query))
+}
+
+func (store *SQLiteStore) query(query string, args ...any) ([]structures.Task, error) {
+	rows, err := store.conn().Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tasks: %w", err)
+	}
+	defer rows.Close()
+
+	tasks := make([]structures.Task, 0)
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("failed to read task row: %w", err)
+		}
+		var task structures.Task
+		if err := json.Unmarshal([]byte(data), &task); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal task: %w", err)
+		}
+		tasks = append(tasks, task)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query tasks: %w", err)
+	}
+	return tasks, nil
+}
+
+// sqliteSearchText - строка для поиска в нижнем регистре. lower() в SQLite понимает только ASCII,
+// поэтому регистр приводится на стороне Go
+func sqliteSearchText(task structures.Task) string {
+	return strings.ToLower(task.TaskName) + "\x00" + strings.ToLower(task.TaskDescription)
+}
@@ -0,0 +1,114 @@
+package task_manager
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/TaskTrackerCLI/structures"
+)
+
+// Store - интерфейс хранилища тасков, от которого зависит TaskManager.
+// Реализации не обязаны быть потокобезопасными.
+type Store interface {
+	// Load - загружает таски из постоянного хранилища
+	Load() error
+	// Save - сохраняет текущее состояние в постоянное хранилище
+	Save() error
+	// Get - возвращает таск по id
+	Get(id int) (structures.Task, bool, error)
+	// Put - добавляет или заменяет таск с task.TaskId
+	Put(task structures.Task) error
+	// Delete - удаляет таск по id, отсутствие таска ошибкой не считается
+	Delete(id int) error
+	// List - возвращает все таски в произвольном порядке
+	List() ([]structures.Task, error)
+	// NextID - выдает id для нового таска. Счетчик сохраняется вместе с тасками и никогда
+	// не уменьшается, поэтому id удаленных тасков не выдаются повторно
+	NextID() (int, error)
+	// Meta - возвращает служебное значение по ключу (например, историю отмены)
+	Meta(key string) (json.RawMessage, bool, error)
+	// SetMeta - сохраняет служебное значение по ключу, nil удаляет ключ
+	SetMeta(key string, value json.RawMessage) error
+}
+
+// ActiveStore - хранилище, которое умеет само отбирать таски рабочего набора, без корзины и архива
+type ActiveStore interface {
+	ListActive() ([]structures.Task, error)
+}
+
+// ArchiveStore - хранилище, которое держит архив отдельно и умеет не читать его, когда архивные таски не нужны
+type ArchiveStore interface {
+	// ListUnarchived - таски рабочего набора и корзины, без архива
+	ListUnarchived() ([]structures.Task, error)
+	// ListArchivedSince - архивные таски, убранные в архив не раньше since
+	ListArchivedSince(since time.Time) ([]structures.Task, error)
+}
+
+// StatusFilterStore - хранилище, которое умеет само отбирать таски по статусу. Таски из корзины и архива не возвращаются
+type StatusFilterStore interface {
+	ListByStatus(status string) ([]structures.Task, error)
+}
+
+// SearchStore - хранилище, которое умеет само искать таски по подстроке в имени и описании.
+// Таски из корзины и архива не возвращаются
+type SearchStore interface {
+	Search(query string) ([]structures.Task, error)
+}
+
+// Виды операций TaskManager
+const (
+	OperationAdd    = "add"
+	OperationUpdate = "update"
+	OperationDelete = "delete"
+	OperationStatus = "status"
+	OperationClean  = "clean"
+	OperationUndo   = "undo"
+	OperationRedo   = "redo"
+	// OperationRestore - возврат таска из корзины
+	OperationRestore = "restore"
+	// OperationEmptyTrash - окончательное удаление тасков из корзины
+	OperationEmptyTrash = "empty_trash"
+	// OperationUnarchive - возврат таска из архива
+	OperationUnarchive = "unarchive"
+	// OperationTag - добавление или снятие метки
+	OperationTag = "tag"
+	// OperationProject - создание, переименование или удаление проекта
+	OperationProject = "project"
+	// OperationDepend - добавление или снятие зависимости между тасками
+	OperationDepend = "depend"
+	// OperationTime - запуск и остановка таймера, ручной учет времени
+	OperationTime = "time"
+	// OperationComment - добавление заметки к таску
+	OperationComment = "comment"
+	// OperationChecklist - добавление, отметка и удаление пунктов чек-листа
+	OperationChecklist = "checklist"
+)
+
+// Change - изменение одного таска в рамках операции. Before == nil для созданного таска,
+// After == nil для удаленного
+type Change struct {
+	TaskId int              `json:"task_id"`
+	Before *structures.Task `json:"before,omitempty"`
+	After  *structures.Task `json:"after,omitempty"`
+}
+
+// MetaChange - изменение служебного значения в рамках операции, nil - ключа нет
+type MetaChange struct {
+	Key    string          `json:"key"`
+	Before json.RawMessage `json:"before,omitempty"`
+	After  json.RawMessage `json:"after,omitempty"`
+}
+
+// Operation - одна изменяющая операция TaskManager со всеми затронутыми тасками и служебными значениями
+type Operation struct {
+	Kind    string       `json:"kind"`
+	At      string       `json:"at"`
+	Changes []Change     `json:"changes"`
+	Meta    []MetaChange `json:"meta,omitempty"`
+}
+
+// OperationStore - хранилище, которому важно, какой операцией вызваны изменения (например, журнал событий).
+// Record вызывается перед Save
+type OperationStore interface {
+	Record(op Operation) error
+}
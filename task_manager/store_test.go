@@ -0,0 +1,341 @@
+package task_manager
+
+import (
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/TaskTrackerCLI/structures"
+)
+
+// TestStores - проверяет базовые операции (put, get, delete, list) у всех реализаций Store.
+func TestStores(t *testing.T) {
+	stores := map[string]func(t *testing.T) Store{
+		"MemoryStore": func(t *testing.T) Store {
+			return NewMemoryStore()
+		},
+		"JSONFileStore": func(t *testing.T) Store {
+			return NewJSONFileStore(filepath.Join(t.TempDir(), "tasks.json"))
+		},
+		"JournalStore": func(t *testing.T) Store {
+			return NewJournalStore(filepath.Join(t.TempDir(), "tasks.json"))
+		},
+		"SQLiteStore": func(t *testing.T) Store {
+			store := NewSQLiteStore(filepath.Join(t.TempDir(), "tasks.db"))
+			t.Cleanup(func() {
+				if err := store.Close(); err != nil {
+					t.Logf("Failed to close database: %v", err)
+				}
+			})
+			return store
+		},
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			store := newStore(t)
+			if err := store.Load(); err != nil {
+				t.Fatalf("Load() error = %v", err)
+			}
+
+			for id := 1; id <= 3; id++ {
+				if err := store.Put(structures.Task{TaskId: id, TaskName: "task", TaskStatus: StatusTodo}); err != nil {
+					t.Fatalf("Put() error = %v", err)
+				}
+			}
+			if err := store.Delete(2); err != nil {
+				t.Fatalf("Delete() error = %v", err)
+			}
+			if err := store.Delete(999); err != nil {
+				t.Errorf("Delete() of missing task error = %v, want nil", err)
+			}
+
+			if _, ok, _ := store.Get(2); ok {
+				t.Errorf("Get(2) found deleted task")
+			}
+			task, ok, err := store.Get(3)
+			if err != nil || !ok || task.TaskId != 3 {
+				t.Errorf("Get(3) = %v, %v, %v", task, ok, err)
+			}
+
+			tasks, err := store.List()
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			if len(tasks) != 2 {
+				t.Errorf("List() returned %d tasks, want 2", len(tasks))
+			}
+			if err := store.Save(); err != nil {
+				t.Fatalf("Save() error = %v", err)
+			}
+		})
+	}
+}
+
+// TestJSONFileStoreRoundTrip - проверяет, что сохраненные таски читаются новым экземпляром хранилища.
+func TestJSONFileStoreRoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+
+	tm, err := NewTaskManager(path)
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+	id, err := tm.AddTask("Persisted", "Survives reload")
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Fatalf("tasks file was not written: %v", err)
+	}
+
+	reloaded, err := NewTaskManager(path)
+	if err != nil {
+		t.Fatalf("Failed to reload TaskManager: %v", err)
+	}
+	task, ok, err := reloaded.GetTask(id)
+	if err != nil || !ok {
+		t.Fatalf("GetTask(%d) after reload = %v, %v", id, ok, err)
+	}
+	if task.TaskName != "Persisted" || task.TaskDescription != "Survives reload" {
+		t.Errorf("Reloaded task mismatch: %+v", task)
+	}
+
+	nextID, err := reloaded.AddTask("Next", "")
+	if err != nil {
+		t.Fatalf("AddTask() after reload error = %v", err)
+	}
+	if nextID != id+1 {
+		t.Errorf("AddTask() after reload returned id %d, want %d", nextID, id+1)
+	}
+}
+
+// TestSQLiteStoreQueries - проверяет фильтрацию по статусу и поиск, выполняемые на стороне SQL.
+func TestSQLiteStoreQueries(t *testing.T) {
+	store := NewSQLiteStore(filepath.Join(t.TempDir(), "tasks.db"))
+	tm, err := NewTaskManagerWithStore(store)
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+	t.Cleanup(func() {
+		if err := tm.Close(); err != nil {
+			t.Logf("Failed to close TaskManager: %v", err)
+		}
+	})
+
+	for _, name := range []string{"Write report", "Покормить кота", "Review PR"} {
+		if _, err := tm.AddTask(name, "description of "+name); err != nil {
+			t.Fatalf("AddTask() error = %v", err)
+		}
+	}
+	if _, err := tm.MarkTaskAsDone(2); err != nil {
+		t.Fatalf("MarkTaskAsDone() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		list    func() ([]structures.Task, error)
+		wantIDs []int
+	}{
+		{name: "Status DONE", list: tm.ListDoneTasks, wantIDs: []int{2}},
+		{name: "Status TODO", list: tm.ListTodoTasks, wantIDs: []int{1, 3}},
+		{name: "Search ASCII case-insensitive", list: func() ([]structures.Task, error) { return tm.SearchTasks("REPORT") }, wantIDs: []int{1}},
+		{name: "Search Cyrillic case-insensitive", list: func() ([]structures.Task, error) { return tm.SearchTasks("КОТА") }, wantIDs: []int{2}},
+		{name: "Search in description", list: func() ([]structures.Task, error) { return tm.SearchTasks("description of r") }, wantIDs: []int{3}},
+		{name: "Search no match", list: func() ([]structures.Task, error) { return tm.SearchTasks("missing") }, wantIDs: []int{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tasks, err := tt.list()
+			if err != nil {
+				t.Fatalf("query error = %v", err)
+			}
+			if len(tasks) != len(tt.wantIDs) {
+				t.Fatalf("got %d tasks, want %d", len(tasks), len(tt.wantIDs))
+			}
+			for i, task := range tasks {
+				if task.TaskId != tt.wantIDs[i] {
+					t.Errorf("task[%d].TaskId = %d, want %d", i, task.TaskId, tt.wantIDs[i])
+				}
+			}
+		})
+	}
+}
+
+// TestJSONFileStoreBackup - проверяет ротацию .bak и загрузку из резервной копии при поврежденном файле.
+func TestJSONFileStoreBackup(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tasks.json")
+
+	tm, err := NewTaskManager(path)
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+	if _, err := tm.AddTask("First", ""); err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	if _, err := os.Stat(path + ".bak"); !os.IsNotExist(err) {
+		t.Errorf("backup must not exist after the first save, stat error = %v", err)
+	}
+	if _, err := tm.AddTask("Second", ""); err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+
+	backup := NewJSONFileStore(path + ".bak")
+	if err := backup.Load(); err != nil {
+		t.Fatalf("Failed to load backup: %v", err)
+	}
+	if tasks, _ := backup.List(); len(tasks) != 1 {
+		t.Errorf("backup holds %d tasks, want the previous version with 1 task", len(tasks))
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir() error = %v", err)
+	}
+	for _, entry := range entries {
+		if strings.Contains(entry.Name(), ".tmp-") {
+			t.Errorf("temporary file %s was left behind", entry.Name())
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(`{"1": {"task_id": 1, "task_na`), 0644); err != nil {
+		t.Fatalf("Failed to corrupt tasks file: %v", err)
+	}
+	recovered, err := NewTaskManager(path)
+	if err != nil {
+		t.Fatalf("NewTaskManager() on corrupted file error = %v, want fallback to backup", err)
+	}
+	if _, ok, _ := recovered.GetTask(1); !ok {
+		t.Errorf("task 1 was not recovered from backup")
+	}
+
+	if err := os.WriteFile(path+".bak", []byte("garbage"), 0644); err != nil {
+		t.Fatalf("Failed to corrupt backup file: %v", err)
+	}
+	if _, err := NewTaskManager(path); err == nil {
+		t.Errorf("NewTaskManager() error = nil, want error when both files are corrupted")
+	}
+}
+
+// TestIDsAreNeverReused - после удаления последнего таска или clean новый таск получает новый id,
+// в том числе после перезапуска.
+func TestIDsAreNeverReused(t *testing.T) {
+	managers := map[string]func(t *testing.T) func() *TaskManager{
+		"MemoryStore": func(t *testing.T) func() *TaskManager {
+			store := NewMemoryStore()
+			return func() *TaskManager {
+				tm, err := NewTaskManagerWithStore(store)
+				if err != nil {
+					t.Fatalf("Failed to create TaskManager: %v", err)
+				}
+				return tm
+			}
+		},
+		"JSONFileStore": func(t *testing.T) func() *TaskManager {
+			path := filepath.Join(t.TempDir(), "tasks.json")
+			return func() *TaskManager {
+				tm, err := NewTaskManager(path)
+				if err != nil {
+					t.Fatalf("Failed to create TaskManager: %v", err)
+				}
+				return tm
+			}
+		},
+		"SQLiteStore": func(t *testing.T) func() *TaskManager {
+			path := filepath.Join(t.TempDir(), "tasks.db")
+			return func() *TaskManager {
+				tm, err := NewTaskManagerWithStore(NewSQLiteStore(path))
+				if err != nil {
+					t.Fatalf("Failed to create TaskManager: %v", err)
+				}
+				t.Cleanup(func() { _ = tm.Close() })
+				return tm
+			}
+		},
+	}
+
+	for name, newManagerFactory := range managers {
+		t.Run(name, func(t *testing.T) {
+			open := newManagerFactory(t)
+			tm := open()
+
+			for i := 0; i < 3; i++ {
+				if _, err := tm.AddTask("task", ""); err != nil {
+					t.Fatalf("AddTask() error = %v", err)
+				}
+			}
+			if ok, err := tm.DeleteTask(3); err != nil || !ok {
+				t.Fatalf("DeleteTask(3) = %v, %v", ok, err)
+			}
+			id, err := tm.AddTask("after delete", "")
+			if err != nil {
+				t.Fatalf("AddTask() error = %v", err)
+			}
+			if id != 4 {
+				t.Errorf("AddTask() after delete returned id %d, want 4", id)
+			}
+
+			if _, err := tm.MarkTaskAsDone(4); err != nil {
+				t.Fatalf("MarkTaskAsDone() error = %v", err)
+			}
+			if _, err := tm.CleanDoneTasks(); err != nil {
+				t.Fatalf("CleanDoneTasks() error = %v", err)
+			}
+			if err := tm.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			reopened := open()
+			id, err = reopened.AddTask("after reopen", "")
+			if err != nil {
+				t.Fatalf("AddTask() after reopen error = %v", err)
+			}
+			if id != 5 {
+				t.Errorf("AddTask() after clean and reopen returned id %d, want 5", id)
+			}
+		})
+	}
+}
+
+// TestSQLiteStoreMigratesIDCounter - база, созданная до появления счетчика id, продолжает нумерацию с max(id)+1.
+func TestSQLiteStoreMigratesIDCounter(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.db")
+
+	// база первой версии схемы, без таблицы meta
+	func() {
+		migrations := sqliteMigrations
+		sqliteMigrations = migrations[:1]
+		defer func() { sqliteMigrations = migrations }()
+
+		store := NewSQLiteStore(path)
+		if err := store.Load(); err != nil {
+			t.Fatalf("Load() error = %v", err)
+		}
+		defer store.Close()
+		for _, id := range []int{2, 9} {
+			if _, err := store.db.Exec(`INSERT INTO tasks (id, name, description, status, created_at, updated_at, search_text, data)
+				VALUES (?, 'old', '', 'TODO', '', '', 'old', ?)`, id, `{"task_id":`+strconv.Itoa(id)+`,"task_name":"old","task_status":"TODO"}`); err != nil {
+				t.Fatalf("Failed to insert legacy row: %v", err)
+			}
+		}
+	}()
+
+	tm, err := NewTaskManagerWithStore(NewSQLiteStore(path))
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+	t.Cleanup(func() { _ = tm.Close() })
+	id, err := tm.AddTask("new", "")
+	if err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+	if id != 10 {
+		t.Errorf("AddTask() on migrated database returned id %d, want 10", id)
+	}
+	if task, ok, err := tm.GetTask(9); err != nil || !ok || task.TaskPriority != DefaultPriority {
+		t.Errorf("legacy task after migration = %+v, %v, %v, want priority %s", task, ok, err, DefaultPriority)
+	}
+}
@@ -0,0 +1,180 @@
+package task_manager
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TaskTrackerCLI/structures"
+)
+
+// ErrOpenSubtasks - таск нельзя отметить выполненным, пока у него есть невыполненные подзадачи
+var ErrOpenSubtasks = errors.New("task has open subtasks")
+
+// childrenByParent - подзадачи рабочего набора по id родителя, в порядке tasks
+func childrenByParent(tasks []structures.Task) map[int][]structures.Task {
+	children := make(map[int][]structures.Task)
+	for _, task := range tasks {
+		if task.TaskParentId != 0 {
+			children[task.TaskParentId] = append(children[task.TaskParentId], task)
+		}
+	}
+	return children
+}
+
+// descendants - все подзадачи таска id на любой глубине
+func descendants(tasks []structures.Task, id int) []structures.Task {
+	children := childrenByParent(tasks)
+	var result []structures.Task
+	queue := []int{id}
+	for len(queue) > 0 {
+		for _, child := range children[queue[0]] {
+			result = append(result, child)
+			queue = append(queue, child.TaskId)
+		}
+		queue = queue[1:]
+	}
+	return result
+}
+
+// resolveParent - id родителя для task_parent. Пусто, 0 или none - таск верхнего уровня.
+// Родитель должен существовать и не может быть самим таском или его подзадачей
+func resolveParent(store Store, taskId int, value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" || value == "0" || strings.EqualFold(value, "none") {
+		return 0, nil
+	}
+	parentId, err := strconv.Atoi(value)
+	if err != nil {
+		return 0, fmt.Errorf("parent task ID must be an integer: %w", err)
+	}
+	for id := parentId; id != 0; {
+		if id == taskId {
+			return 0, fmt.Errorf("task %d cannot be a subtask of itself or of its own subtask", taskId)
+		}
+		parent, ok, err := getActiveTask(store, id)
+		if err != nil {
+			return 0, err
+		}
+		if !ok {
+			if id == parentId {
+				return 0, fmt.Errorf("parent task %d not found", parentId)
+			}
+			break
+		}
+		id = parent.TaskParentId
+	}
+	return parentId, nil
+}
+
+// openSubtasks - невыполненные подзадачи таска id на любой глубине
+func openSubtasks(store Store, id int) ([]structures.Task, error) {
+	tasks, err := listActive(store)
+	if err != nil {
+		return nil, err
+	}
+	var open []structures.Task
+	for _, task := range descendants(tasks, id) {
+		if task.TaskStatus != StatusDone {
+			open = append(open, task)
+		}
+	}
+	return open, nil
+}
+
+// MarkTaskTreeAsDone - отмечает выполненными таск и все его открытые подзадачи одной операцией.
+// Если рабочий процесс не разрешает перевести в DONE хотя бы один из них, не меняется ничего.
+// Возвращает id следующего экземпляра серии, если таск id повторяющийся и он был создан, иначе 0
+func (taskManager *TaskManager) MarkTaskTreeAsDone(id int) (bool, int, error) {
+	var found bool
+	var next int
+	err := taskManager.mutate(OperationStatus, func(store Store) error {
+		task, ok, err := getActiveTask(store, id)
+		if err != nil || !ok {
+			return err
+		}
+		found = true
+		open, err := openSubtasks(store, id)
+		if err != nil {
+			return err
+		}
+		now := time.Now()
+		for _, subtask := range append(open, task) {
+			if err := taskManager.Workflow.checkTransition(subtask.TaskId, subtask.TaskStatus, StatusDone); err != nil {
+				return err
+			}
+			subtask.TaskStatus = StatusDone
+			subtask.TaskUpdatedAt = now.Format(time.RFC3339)
+			created, err := scheduleNextOccurrence(store, &subtask, now)
+			if err != nil {
+				return err
+			}
+			if subtask.TaskId == id {
+				next = created
+			}
+			if err := store.Put(subtask); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, 0, fmt.Errorf("failed to save task status change: %w", err)
+	}
+	return found, next, nil
+}
+
+// TreeNode - таск с подзадачами и долей выполненных подзадач
+type TreeNode struct {
+	Task     structures.Task
+	Children []TreeNode
+	// Percent - процент выполненных среди всех подзадач на любой глубине, у листьев 0 или 100 по статусу
+	Percent int
+	// total и done - количество подзадач на любой глубине и выполненных среди них
+	total, done int
+}
+
+// TaskTree - иерархия тасков рабочего набора. Подзадачи, родитель которых в корзине или архиве,
+// показываются на верхнем уровне. Порядок на каждом уровне как у ListAllTasks
+func (taskManager *TaskManager) TaskTree() ([]TreeNode, error) {
+	tasks, err := taskManager.ListAllTasks()
+	if err != nil {
+		return nil, err
+	}
+	present := make(map[int]bool, len(tasks))
+	for _, task := range tasks {
+		present[task.TaskId] = true
+	}
+	children := childrenByParent(tasks)
+
+	var build func(task structures.Task) TreeNode
+	build = func(task structures.Task) TreeNode {
+		node := TreeNode{Task: task}
+		for _, child := range children[task.TaskId] {
+			childNode := build(child)
+			node.Children = append(node.Children, childNode)
+			node.total += childNode.total + 1
+			node.done += childNode.done
+			if child.TaskStatus == StatusDone {
+				node.done++
+			}
+		}
+		switch {
+		case node.total > 0:
+			node.Percent = node.done * 100 / node.total
+		case task.TaskStatus == StatusDone:
+			node.Percent = 100
+		}
+		return node
+	}
+
+	var roots []TreeNode
+	for _, task := range tasks {
+		if task.TaskParentId == 0 || !present[task.TaskParentId] {
+			roots = append(roots, build(task))
+		}
+	}
+	return roots, nil
+}
@@ -0,0 +1,89 @@
+package task_manager
+
+import (
+	"errors"
+	"testing"
+)
+
+// TestSubtasks - проверяет привязку подзадач, защиту от циклов, закрытие родителя, дерево с прогрессом
+// и каскадное удаление в корзину с восстановлением.
+func TestSubtasks(t *testing.T) {
+	tm, err := NewTaskManagerWithStore(NewMemoryStore())
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+
+	// 1 <- 2 <- 3, 1 <- 4, 5 отдельно
+	parents := []string{"", "1", "2", "1", ""}
+	for _, parent := range parents {
+		if _, err := tm.AddTaskWithValues("task", "", map[string]string{"task_parent": parent}); err != nil {
+			t.Fatalf("AddTaskWithValues(parent %q) error = %v", parent, err)
+		}
+	}
+	if _, err := tm.AddTaskWithValues("task", "", map[string]string{"task_parent": "42"}); err == nil {
+		t.Errorf("AddTaskWithValues() with a missing parent error = nil")
+	}
+	for _, parent := range []string{"1", "3"} {
+		if _, err := tm.UpdateTask(1, map[string]string{"task_parent": parent}); err == nil {
+			t.Errorf("UpdateTask(1, parent %s) error = nil, want a cycle error", parent)
+		}
+	}
+
+	if _, err := tm.MarkTaskAsDone(1); !errors.Is(err, ErrOpenSubtasks) {
+		t.Fatalf("MarkTaskAsDone() with open subtasks error = %v, want ErrOpenSubtasks", err)
+	}
+	if _, err := tm.MarkTaskAsDone(3); err != nil {
+		t.Fatalf("MarkTaskAsDone(3) error = %v", err)
+	}
+
+	tree, err := tm.TaskTree()
+	if err != nil {
+		t.Fatalf("TaskTree() error = %v", err)
+	}
+	if len(tree) != 2 || tree[0].Task.TaskId != 1 || len(tree[0].Children) != 2 {
+		t.Fatalf("TaskTree() = %+v, want roots 1 and 5 with 1 having two children", tree)
+	}
+	if got := tree[0].Percent; got != 33 {
+		t.Errorf("root percent = %d, want 33", got)
+	}
+	if got := tree[0].Children[0].Percent; got != 100 {
+		t.Errorf("task 2 percent = %d, want 100", got)
+	}
+
+	if ok, _, err := tm.MarkTaskTreeAsDone(1); !ok || err != nil {
+		t.Fatalf("MarkTaskTreeAsDone() = %v, %v", ok, err)
+	}
+	if done, _ := tm.ListDoneTasks(); len(done) != 4 {
+		t.Errorf("after MarkTaskTreeAsDone %d tasks are DONE, want 4", len(done))
+	}
+	if _, ok, err := tm.Undo(); !ok || err != nil {
+		t.Fatalf("Undo() = %v, %v", ok, err)
+	}
+	if done, _ := tm.ListDoneTasks(); len(done) != 1 {
+		t.Errorf("Undo() of MarkTaskTreeAsDone left %d tasks DONE, want 1", len(done))
+	}
+
+	if _, err := tm.DeleteTask(1); err != nil {
+		t.Fatalf("DeleteTask(1) error = %v", err)
+	}
+	if tasks, _ := tm.ListAllTasks(); len(tasks) != 1 {
+		t.Errorf("after deleting the root %d tasks remain, want 1", len(tasks))
+	}
+	if ok, err := tm.RestoreTask(1); !ok || err != nil {
+		t.Fatalf("RestoreTask(1) = %v, %v", ok, err)
+	}
+	if tasks, _ := tm.ListAllTasks(); len(tasks) != 5 {
+		t.Errorf("after restoring the root %d tasks are active, want 5", len(tasks))
+	}
+
+	if _, err := tm.DeleteTask(2); err != nil {
+		t.Fatalf("DeleteTask(2) error = %v", err)
+	}
+	if ok, err := tm.RestoreTask(3); !ok || err != nil {
+		t.Fatalf("RestoreTask(3) = %v, %v", ok, err)
+	}
+	task, _, _ := tm.GetTask(3)
+	if task.TaskParentId != 0 {
+		t.Errorf("restored subtask of a trashed parent has parent %d, want 0", task.TaskParentId)
+	}
+}
@@ -0,0 +1,159 @@
+package task_manager
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/TaskTrackerCLI/structures"
+)
+
+// normalizeTag - метка в нижнем регистре. Метки не могут быть пустыми и содержать пробелы или запятые
+func normalizeTag(tag string) (string, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	if tag == "" {
+		return "", fmt.Errorf("tag must not be empty")
+	}
+	if strings.IndexFunc(tag, func(r rune) bool { return unicode.IsSpace(r) || r == ',' }) >= 0 {
+		return "", fmt.Errorf("invalid tag %q: tags must not contain spaces or commas", tag)
+	}
+	return tag, nil
+}
+
+// ParseTags - разбирает метки через запятую в отсортированный список без повторов. Пустая строка - нет меток
+func ParseTags(value string) ([]string, error) {
+	var tags []string
+	for _, part := range strings.Split(value, ",") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		tag, err := normalizeTag(part)
+		if err != nil {
+			return nil, err
+		}
+		tags = addTag(tags, tag)
+	}
+	return tags, nil
+}
+
+func hasTag(tags []string, tag string) bool {
+	index := sort.SearchStrings(tags, tag)
+	return index < len(tags) && tags[index] == tag
+}
+
+// addTag - новый отсортированный список с меткой tag, исходный не меняется
+func addTag(tags []string, tag string) []string {
+	index := sort.SearchStrings(tags, tag)
+	if index < len(tags) && tags[index] == tag {
+		return tags
+	}
+	result := make([]string, 0, len(tags)+1)
+	result = append(result, tags[:index]...)
+	result = append(result, tag)
+	return append(result, tags[index:]...)
+}
+
+// removeTag - новый список без метки tag, исходный не меняется
+func removeTag(tags []string, tag string) []string {
+	index := sort.SearchStrings(tags, tag)
+	if index == len(tags) || tags[index] != tag {
+		return tags
+	}
+	if len(tags) == 1 {
+		return nil
+	}
+	result := make([]string, 0, len(tags)-1)
+	result = append(result, tags[:index]...)
+	return append(result, tags[index+1:]...)
+}
+
+// FilterByTags - оставляет таски со всеми метками tags (matchAll) или хотя бы с одной из них
+func FilterByTags(tasks []structures.Task, tags []string, matchAll bool) []structures.Task {
+	result := make([]structures.Task, 0, len(tasks))
+	for _, task := range tasks {
+		matched := 0
+		for _, tag := range tags {
+			if hasTag(task.TaskTags, tag) {
+				matched++
+			}
+		}
+		if (matchAll && matched == len(tags)) || (!matchAll && matched > 0) {
+			result = append(result, task)
+		}
+	}
+	return result
+}
+
+// AddTag - добавляет метку таску. false, если таска нет
+func (taskManager *TaskManager) AddTag(id int, tag string) (bool, error) {
+	tag, err := normalizeTag(tag)
+	if err != nil {
+		return false, err
+	}
+	return taskManager.changeTags(id, func(tags []string) []string {
+		return addTag(tags, tag)
+	})
+}
+
+// RemoveTag - снимает метку с таска. false, если таска нет
+func (taskManager *TaskManager) RemoveTag(id int, tag string) (bool, error) {
+	tag = strings.ToLower(strings.TrimSpace(tag))
+	return taskManager.changeTags(id, func(tags []string) []string {
+		return removeTag(tags, tag)
+	})
+}
+
+func (taskManager *TaskManager) changeTags(id int, change func(tags []string) []string) (bool, error) {
+	var found bool
+	err := taskManager.mutate(OperationTag, func(store Store) error {
+		task, ok, err := getActiveTask(store, id)
+		if err != nil || !ok {
+			return err
+		}
+		found = true
+		tags := change(task.TaskTags)
+		if strings.Join(tags, ",") == strings.Join(task.TaskTags, ",") {
+			return nil
+		}
+		task.TaskTags = tags
+		task.TaskUpdatedAt = time.Now().Format(time.RFC3339)
+		return store.Put(task)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to save tags: %w", err)
+	}
+	return found, nil
+}
+
+// TagCount - метка и количество тасков с ней
+type TagCount struct {
+	Tag   string
+	Count int
+}
+
+// TagCounts - все метки тасков рабочего набора, начиная с самых частых
+func (taskManager *TaskManager) TagCounts() ([]TagCount, error) {
+	tasks, err := taskManager.ListAllTasks()
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for _, task := range tasks {
+		for _, tag := range task.TaskTags {
+			counts[tag]++
+		}
+	}
+	result := make([]TagCount, 0, len(counts))
+	for tag, count := range counts {
+		result = append(result, TagCount{Tag: tag, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		return result[i].Tag < result[j].Tag
+	})
+	return result, nil
+}
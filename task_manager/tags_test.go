@@ -0,0 +1,88 @@
+package task_manager
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestTags - проверяет добавление и снятие меток, фильтр с семантикой И/ИЛИ, подсчет и сохранение меток в файле.
+func TestTags(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tasks.json")
+	tm, err := NewTaskManager(path)
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+
+	for _, tags := range []string{"Backend, urgent", "backend", "frontend,urgent,backend", ""} {
+		if _, err := tm.AddTaskWithValues("task", "", map[string]string{"task_tags": tags}); err != nil {
+			t.Fatalf("AddTaskWithValues(tags %q) error = %v", tags, err)
+		}
+	}
+	if _, err := tm.AddTaskWithValues("task", "", map[string]string{"task_tags": "two words"}); err == nil {
+		t.Errorf("AddTaskWithValues() with a space in a tag error = nil")
+	}
+	if ok, err := tm.AddTag(4, "Docs"); !ok || err != nil {
+		t.Fatalf("AddTag() = %v, %v", ok, err)
+	}
+	if ok, err := tm.RemoveTag(3, "urgent"); !ok || err != nil {
+		t.Fatalf("RemoveTag() = %v, %v", ok, err)
+	}
+	if ok, err := tm.AddTag(99, "docs"); ok || err != nil {
+		t.Errorf("AddTag() on a missing task = %v, %v, want false", ok, err)
+	}
+
+	reloaded, err := NewTaskManager(path)
+	if err != nil {
+		t.Fatalf("Failed to reload TaskManager: %v", err)
+	}
+	tasks, err := reloaded.ListAllTasks()
+	if err != nil {
+		t.Fatalf("ListAllTasks() error = %v", err)
+	}
+	wantTags := []string{"backend,urgent", "backend", "backend,frontend", "docs"}
+	for i, task := range tasks {
+		if got := strings.Join(task.TaskTags, ","); got != wantTags[i] {
+			t.Errorf("task %d tags = %q, want %q", task.TaskId, got, wantTags[i])
+		}
+	}
+
+	tests := []struct {
+		name     string
+		tags     []string
+		matchAll bool
+		wantIDs  []int
+	}{
+		{name: "AND", tags: []string{"backend", "urgent"}, matchAll: true, wantIDs: []int{1}},
+		{name: "OR", tags: []string{"urgent", "docs"}, matchAll: false, wantIDs: []int{1, 4}},
+		{name: "Single tag", tags: []string{"backend"}, matchAll: true, wantIDs: []int{1, 2, 3}},
+		{name: "Unknown tag", tags: []string{"ops"}, matchAll: false, wantIDs: []int{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filtered := FilterByTags(tasks, tt.tags, tt.matchAll)
+			if len(filtered) != len(tt.wantIDs) {
+				t.Fatalf("got %d tasks, want %v", len(filtered), tt.wantIDs)
+			}
+			for i, task := range filtered {
+				if task.TaskId != tt.wantIDs[i] {
+					t.Errorf("task[%d] = %d, want %d", i, task.TaskId, tt.wantIDs[i])
+				}
+			}
+		})
+	}
+
+	counts, err := reloaded.TagCounts()
+	if err != nil {
+		t.Fatalf("TagCounts() error = %v", err)
+	}
+	want := []TagCount{{"backend", 3}, {"docs", 1}, {"frontend", 1}, {"urgent", 1}}
+	if len(counts) != len(want) {
+		t.Fatalf("TagCounts() = %v, want %v", counts, want)
+	}
+	for i := range counts {
+		if counts[i] != want[i] {
+			t.Errorf("TagCounts()[%d] = %v, want %v", i, counts[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,256 @@
+package task_manager
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/TaskTrackerCLI/dates"
+	"github.com/TaskTrackerCLI/structures"
+)
+
+// activeTimerKey - ключ служебного значения в Store, под которым хранится запущенный таймер
+const activeTimerKey = "active_timer"
+
+// ErrTimerRunning - таймер уже запущен, одновременно может идти только один
+var ErrTimerRunning = errors.New("a timer is already running")
+
+// Timer - запущенный таймер
+type Timer struct {
+	TaskId int    `json:"task_id"`
+	Start  string `json:"start"`
+	Actor  string `json:"actor"`
+}
+
+// Elapsed - сколько времени идет таймер к моменту now
+func (timer Timer) Elapsed(now time.Time) time.Duration {
+	start, err := time.Parse(time.RFC3339, timer.Start)
+	if err != nil {
+		return 0
+	}
+	return now.Sub(start)
+}
+
+func loadTimer(store Store) (Timer, bool, error) {
+	var timer Timer
+	raw, ok, err := store.Meta(activeTimerKey)
+	if err != nil || !ok {
+		return timer, false, err
+	}
+	if err := json.Unmarshal(raw, &timer); err != nil {
+		return timer, false, fmt.Errorf("failed to read active timer: %w", err)
+	}
+	return timer, true, nil
+}
+
+// saveTimer - запоминает запущенный таймер, nil останавливает его
+func saveTimer(store Store, timer *Timer) error {
+	if timer == nil {
+		return store.SetMeta(activeTimerKey, nil)
+	}
+	raw, err := json.Marshal(timer)
+	if err != nil {
+		return fmt.Errorf("failed to write active timer: %w", err)
+	}
+	return store.SetMeta(activeTimerKey, raw)
+}
+
+// StartTimer - запускает таймер на таске id и, если рабочий процесс позволяет, переводит таск в IN_PROGRESS.
+// Если таймер уже идет, возвращается ErrTimerRunning. false, если таска нет. Как и SetStatus, возвращает
+// id незавершенных тасков, от которых зависит таск
+func (taskManager *TaskManager) StartTimer(id int) (bool, []int, error) {
+	var found bool
+	var blockers []int
+	err := taskManager.mutate(OperationTime, func(store Store) error {
+		if running, ok, err := loadTimer(store); err != nil || ok {
+			if err != nil {
+				return err
+			}
+			return fmt.Errorf("%w on task %d, stop it first", ErrTimerRunning, running.TaskId)
+		}
+		task, ok, err := getActiveTask(store, id)
+		if err != nil || !ok {
+			return err
+		}
+		found = true
+		if blockers, err = taskBlockers(store, id); err != nil {
+			return err
+		}
+		now := time.Now()
+		if task.TaskStatus != StatusInProgress && taskManager.Workflow.HasStatus(StatusInProgress) &&
+			taskManager.Workflow.Allows(task.TaskStatus, StatusInProgress) {
+			task.TaskStatus = StatusInProgress
+			task.TaskUpdatedAt = now.Format(time.RFC3339)
+			if err := store.Put(task); err != nil {
+				return err
+			}
+		}
+		return saveTimer(store, &Timer{TaskId: id, Start: now.Format(time.RFC3339), Actor: taskManager.Actor})
+	})
+	if err != nil {
+		return false, nil, fmt.Errorf("failed to start timer: %w", err)
+	}
+	return found, blockers, nil
+}
+
+// StopTimer - останавливает таймер и записывает отрезок времени в таск. false, если таймер не запущен
+func (taskManager *TaskManager) StopTimer() (Timer, bool, error) {
+	var timer Timer
+	var found bool
+	err := taskManager.mutate(OperationTime, func(store Store) error {
+		var err error
+		timer, found, err = loadTimer(store)
+		if err != nil || !found {
+			return err
+		}
+		if err := saveTimer(store, nil); err != nil {
+			return err
+		}
+		// таск мог попасть в корзину или архив, время все равно на него потрачено
+		task, ok, err := store.Get(timer.TaskId)
+		if err != nil || !ok {
+			return err
+		}
+		task.TaskWorklog = append(task.TaskWorklog, structures.WorklogEntry{
+			Start: timer.Start,
+			End:   time.Now().Format(time.RFC3339),
+			Actor: timer.Actor,
+		})
+		return store.Put(task)
+	})
+	if err != nil {
+		return Timer{}, false, fmt.Errorf("failed to stop timer: %w", err)
+	}
+	return timer, found, nil
+}
+
+// ActiveTimer - запущенный таймер, false если его нет
+func (taskManager *TaskManager) ActiveTimer() (Timer, bool, error) {
+	taskManager.mu.RLock()
+	defer taskManager.mu.RUnlock()
+	return loadTimer(taskManager.store)
+}
+
+// LogWork - вручную записывает на таск id отрезок времени длиной duration, закончившийся в end
+func (taskManager *TaskManager) LogWork(id int, duration time.Duration, end time.Time) (bool, error) {
+	if duration <= 0 {
+		return false, fmt.Errorf("logged time must be positive, got %s", duration)
+	}
+	// время после архивации таска Timesheet не ищет, а в будущем его и не могли потратить
+	if end.After(time.Now()) {
+		return false, fmt.Errorf("logged time cannot end in the future, got %s", end.Format(time.RFC3339))
+	}
+	var found bool
+	err := taskManager.mutate(OperationTime, func(store Store) error {
+		task, ok, err := getActiveTask(store, id)
+		if err != nil || !ok {
+			return err
+		}
+		found = true
+		task.TaskWorklog = append(task.TaskWorklog, structures.WorklogEntry{
+			Start:  end.Add(-duration).Format(time.RFC3339),
+			End:    end.Format(time.RFC3339),
+			Actor:  taskManager.Actor,
+			Manual: true,
+		})
+		return store.Put(task)
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to log time: %w", err)
+	}
+	return found, nil
+}
+
+// entryDuration - длина отрезка, 0 если время в нем не разбирается
+func entryDuration(entry structures.WorklogEntry) time.Duration {
+	start, errStart := time.Parse(time.RFC3339, entry.Start)
+	end, errEnd := time.Parse(time.RFC3339, entry.End)
+	if errStart != nil || errEnd != nil || end.Before(start) {
+		return 0
+	}
+	return end.Sub(start)
+}
+
+// TimeSpent - сколько всего времени записано на таск
+func TimeSpent(task structures.Task) time.Duration {
+	var total time.Duration
+	for _, entry := range task.TaskWorklog {
+		total += entryDuration(entry)
+	}
+	return total
+}
+
+// TaskTime - время, потраченное на таск за период
+type TaskTime struct {
+	Task     structures.Task
+	Duration time.Duration
+}
+
+// DayTime - время, потраченное за день, Day в формате dates.Layout
+type DayTime struct {
+	Day      string
+	Duration time.Duration
+}
+
+// Timesheet - отчет о потраченном времени за период
+type Timesheet struct {
+	// ByTask - по таскам, начиная с тех, на которые ушло больше всего времени
+	ByTask []TaskTime
+	// ByDay - по дням в порядке дат, дни без записей пропускаются
+	ByDay []DayTime
+	Total time.Duration
+}
+
+// Timesheet - время, записанное на все таски, включая архив и корзину, с from по to включительно.
+// Отрезок относится к дню, в который он начался, в часовом поясе from
+func (taskManager *TaskManager) Timesheet(from, to time.Time) (Timesheet, error) {
+	taskManager.mu.RLock()
+	defer taskManager.mu.RUnlock()
+
+	var sheet Timesheet
+	first, last := dates.StartOfDay(from), dates.StartOfDay(to.In(from.Location()))
+	tasks, err := listUnarchived(taskManager.store)
+	if err != nil {
+		return sheet, err
+	}
+	// время записывается только на таски рабочего набора, поэтому таск, убранный в архив до начала
+	// периода, в нем ничего не набрал, и архив целиком читать не нужно
+	archived, err := listArchivedSince(taskManager.store, first)
+	if err != nil {
+		return sheet, err
+	}
+	tasks = append(tasks, archived...)
+	byDay := make(map[string]time.Duration)
+	for _, task := range tasks {
+		var spent time.Duration
+		for _, entry := range task.TaskWorklog {
+			start, err := time.Parse(time.RFC3339, entry.Start)
+			if err != nil {
+				continue
+			}
+			day := dates.StartOfDay(start.In(from.Location()))
+			if day.Before(first) || day.After(last) {
+				continue
+			}
+			duration := entryDuration(entry)
+			spent += duration
+			byDay[dates.Format(day)] += duration
+		}
+		if spent > 0 {
+			sheet.ByTask = append(sheet.ByTask, TaskTime{Task: task, Duration: spent})
+			sheet.Total += spent
+		}
+	}
+	sort.SliceStable(sheet.ByTask, func(i, j int) bool {
+		return sheet.ByTask[i].Duration > sheet.ByTask[j].Duration
+	})
+	for day, duration := range byDay {
+		sheet.ByDay = append(sheet.ByDay, DayTime{Day: day, Duration: duration})
+	}
+	sort.Slice(sheet.ByDay, func(i, j int) bool {
+		return sheet.ByDay[i].Day < sheet.ByDay[j].Day
+	})
+	return sheet, nil
+}
@@ -0,0 +1,92 @@
+package task_manager
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+// TestTimers - проверяет запуск и остановку таймера, запрет второго таймера, ручной учет времени и отчет по периодам.
+func TestTimers(t *testing.T) {
+	tm, err := NewTaskManagerWithStore(NewMemoryStore())
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+	for _, name := range []string{"billing", "support"} {
+		if _, err := tm.AddTask(name, ""); err != nil {
+			t.Fatalf("AddTask() error = %v", err)
+		}
+	}
+
+	if _, ok, err := tm.StopTimer(); ok || err != nil {
+		t.Errorf("StopTimer() without a timer = %v, %v, want false, nil", ok, err)
+	}
+	if ok, _, err := tm.StartTimer(42); ok || err != nil {
+		t.Errorf("StartTimer() on a missing task = %v, %v, want false, nil", ok, err)
+	}
+	if ok, _, err := tm.StartTimer(1); !ok || err != nil {
+		t.Fatalf("StartTimer() = %v, %v", ok, err)
+	}
+	if task, _, _ := tm.GetTask(1); task.TaskStatus != StatusInProgress {
+		t.Errorf("StartTimer() left status %s, want IN_PROGRESS", task.TaskStatus)
+	}
+	if _, _, err := tm.StartTimer(2); !errors.Is(err, ErrTimerRunning) {
+		t.Errorf("second StartTimer() error = %v, want ErrTimerRunning", err)
+	}
+	if timer, ok, err := tm.ActiveTimer(); !ok || err != nil || timer.TaskId != 1 {
+		t.Errorf("ActiveTimer() = %+v, %v, %v", timer, ok, err)
+	}
+	if timer, ok, err := tm.StopTimer(); !ok || err != nil || timer.TaskId != 1 {
+		t.Fatalf("StopTimer() = %+v, %v, %v", timer, ok, err)
+	}
+	if _, ok, _ := tm.ActiveTimer(); ok {
+		t.Errorf("timer is still running after StopTimer()")
+	}
+	if task, _, _ := tm.GetTask(1); len(task.TaskWorklog) != 1 {
+		t.Errorf("StopTimer() recorded %d worklog entries, want 1", len(task.TaskWorklog))
+	}
+
+	day := time.Date(2026, time.March, 2, 18, 0, 0, 0, time.Local)
+	logs := []struct {
+		id       int
+		duration time.Duration
+		end      time.Time
+	}{
+		{id: 1, duration: 90 * time.Minute, end: day},
+		{id: 2, duration: 30 * time.Minute, end: day},
+		{id: 2, duration: 3 * time.Hour, end: day.AddDate(0, 0, 1)},
+		{id: 1, duration: time.Hour, end: day.AddDate(0, 0, 10)},
+	}
+	for _, log := range logs {
+		if ok, err := tm.LogWork(log.id, log.duration, log.end); !ok || err != nil {
+			t.Fatalf("LogWork(%d, %s) = %v, %v", log.id, log.duration, ok, err)
+		}
+	}
+	if _, err := tm.LogWork(1, -time.Hour, day); err == nil {
+		t.Errorf("LogWork() with a negative duration error = nil")
+	}
+	if _, err := tm.LogWork(1, time.Hour, time.Now().Add(time.Hour)); err == nil {
+		t.Errorf("LogWork() ending in the future error = nil")
+	}
+
+	sheet, err := tm.Timesheet(day, day.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("Timesheet() error = %v", err)
+	}
+	if sheet.Total != 5*time.Hour {
+		t.Errorf("Total = %s, want 5h", sheet.Total)
+	}
+	if len(sheet.ByTask) != 2 || sheet.ByTask[0].Task.TaskId != 2 || sheet.ByTask[0].Duration != 210*time.Minute {
+		t.Errorf("ByTask = %+v, want support first with 3h30m", sheet.ByTask)
+	}
+	if len(sheet.ByDay) != 2 || sheet.ByDay[0].Day != "2026-03-02" || sheet.ByDay[0].Duration != 2*time.Hour {
+		t.Errorf("ByDay = %+v, want 2026-03-02 with 2h first", sheet.ByDay)
+	}
+
+	if _, _, err := tm.Undo(); err != nil {
+		t.Fatalf("Undo() error = %v", err)
+	}
+	if task, _, _ := tm.GetTask(1); len(task.TaskWorklog) != 2 {
+		t.Errorf("after Undo() task 1 has %d worklog entries, want 2", len(task.TaskWorklog))
+	}
+}
@@ -0,0 +1,171 @@
+package task_manager
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/TaskTrackerCLI/structures"
+)
+
+// isTrashed - таск находится в корзине
+func isTrashed(task structures.Task) bool {
+	return task.TaskDeletedAt != ""
+}
+
+// isActive - таск входит в рабочий набор: не в корзине и не в архиве
+func isActive(task structures.Task) bool {
+	return !isTrashed(task) && !isArchived(task)
+}
+
+// getActiveTask - таск по id, если он есть и входит в рабочий набор
+func getActiveTask(store Store, id int) (structures.Task, bool, error) {
+	task, ok, err := store.Get(id)
+	if err != nil || !ok || !isActive(task) {
+		return structures.Task{}, false, err
+	}
+	return task, true, nil
+}
+
+// activeTasks - таски рабочего набора, без корзины и архива
+func activeTasks(tasks []structures.Task) []structures.Task {
+	result := tasks[:0]
+	for _, task := range tasks {
+		if isActive(task) {
+			result = append(result, task)
+		}
+	}
+	return result
+}
+
+// listActive - таски рабочего набора. Хранилище с ActiveStore отбирает их само, не читая архив
+func listActive(store Store) ([]structures.Task, error) {
+	if activeStore, ok := store.(ActiveStore); ok {
+		tasks, err := activeStore.ListActive()
+		if err != nil {
+			return nil, err
+		}
+		return activeTasks(tasks), nil
+	}
+	tasks, err := listUnarchived(store)
+	if err != nil {
+		return nil, err
+	}
+	return activeTasks(tasks), nil
+}
+
+// moveToTrash - переносит в корзину таск вместе со всеми его подзадачами
+func moveToTrash(store Store, task structures.Task) error {
+	tasks, err := listUnarchived(store)
+	if err != nil {
+		return err
+	}
+	deletedAt := time.Now().Format(time.RFC3339)
+	for _, descendant := range append([]structures.Task{task}, descendants(tasks, task.TaskId)...) {
+		if !isActive(descendant) {
+			continue
+		}
+		descendant.TaskDeletedAt = deletedAt
+		if err := store.Put(descendant); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListTrash - таски в корзине, начиная с удаленных последними
+func (taskManager *TaskManager) ListTrash() ([]structures.Task, error) {
+	taskManager.mu.RLock()
+	defer taskManager.mu.RUnlock()
+
+	tasks, err := listUnarchived(taskManager.store)
+	if err != nil {
+		return nil, err
+	}
+	trash := make([]structures.Task, 0)
+	for _, task := range tasks {
+		if isTrashed(task) {
+			trash = append(trash, task)
+		}
+	}
+	sort.Slice(trash, func(i, j int) bool {
+		if trash[i].TaskDeletedAt != trash[j].TaskDeletedAt {
+			return trash[i].TaskDeletedAt > trash[j].TaskDeletedAt
+		}
+		return trash[i].TaskId < trash[j].TaskId
+	})
+	return trash, nil
+}
+
+// RestoreTask - возвращает таск из корзины вместе с подзадачами, удаленными вместе с ним.
+// Если родитель таска остался в корзине, таск возвращается на верхний уровень.
+// false, если в корзине нет таска с таким id
+func (taskManager *TaskManager) RestoreTask(id int) (bool, error) {
+	var found bool
+	err := taskManager.mutate(OperationRestore, func(store Store) error {
+		task, ok, err := store.Get(id)
+		if err != nil || !ok || !isTrashed(task) {
+			return err
+		}
+		found = true
+		if task.TaskParentId != 0 {
+			if _, ok, err := getActiveTask(store, task.TaskParentId); err != nil {
+				return err
+			} else if !ok {
+				task.TaskParentId = 0
+			}
+		}
+
+		tasks, err := listUnarchived(store)
+		if err != nil {
+			return err
+		}
+		updatedAt := time.Now().Format(time.RFC3339)
+		for _, restored := range append([]structures.Task{task}, descendants(tasks, id)...) {
+			if restored.TaskDeletedAt != task.TaskDeletedAt {
+				continue
+			}
+			restored.TaskDeletedAt = ""
+			restored.TaskUpdatedAt = updatedAt
+			if err := store.Put(restored); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to restore task: %w", err)
+	}
+	return found, nil
+}
+
+// EmptyTrash - окончательно удаляет таски, пролежавшие в корзине дольше olderThan, 0 удаляет все.
+// Возвращает количество удаленных тасков
+func (taskManager *TaskManager) EmptyTrash(olderThan time.Duration) (int, error) {
+	var count int
+	cutoff := time.Now().Add(-olderThan)
+	err := taskManager.mutate(OperationEmptyTrash, func(store Store) error {
+		tasks, err := listUnarchived(store)
+		if err != nil {
+			return err
+		}
+		for _, task := range tasks {
+			if !isTrashed(task) {
+				continue
+			}
+			// непонятная дата удаления не должна навсегда оставлять таск в корзине
+			if deletedAt, err := time.Parse(time.RFC3339, task.TaskDeletedAt); err == nil && deletedAt.After(cutoff) {
+				continue
+			}
+			if err := store.Delete(task.TaskId); err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to empty trash: %w", err)
+	}
+	return count, nil
+}
@@ -0,0 +1,104 @@
+package task_manager
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TaskTrackerCLI/structures"
+)
+
+// TestTrash - проверяет перенос в корзину, скрытие тасков из корзины в списках и поиске, восстановление и очистку.
+func TestTrash(t *testing.T) {
+	stores := map[string]func(t *testing.T) Store{
+		"MemoryStore": func(t *testing.T) Store {
+			return NewMemoryStore()
+		},
+		"SQLiteStore": func(t *testing.T) Store {
+			return NewSQLiteStore(filepath.Join(t.TempDir(), "tasks.db"))
+		},
+	}
+
+	ids := func(tasks []structures.Task, err error) []int {
+		t.Helper()
+		if err != nil {
+			t.Fatalf("query error = %v", err)
+		}
+		result := make([]int, 0, len(tasks))
+		for _, task := range tasks {
+			result = append(result, task.TaskId)
+		}
+		return result
+	}
+	expect := func(name string, got []int, want ...int) {
+		t.Helper()
+		if len(got) != len(want) {
+			t.Fatalf("%s = %v, want %v", name, got, want)
+		}
+		for i := range got {
+			if got[i] != want[i] {
+				t.Fatalf("%s = %v, want %v", name, got, want)
+			}
+		}
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			tm, err := NewTaskManagerWithStore(newStore(t))
+			if err != nil {
+				t.Fatalf("Failed to create TaskManager: %v", err)
+			}
+			t.Cleanup(func() { _ = tm.Close() })
+
+			for _, name := range []string{"Write report", "Fix bug", "Review report"} {
+				if _, err := tm.AddTask(name, ""); err != nil {
+					t.Fatalf("AddTask() error = %v", err)
+				}
+			}
+			if _, err := tm.MarkTaskAsDone(3); err != nil {
+				t.Fatalf("MarkTaskAsDone() error = %v", err)
+			}
+			if ok, err := tm.DeleteTask(1); !ok || err != nil {
+				t.Fatalf("DeleteTask(1) = %v, %v", ok, err)
+			}
+			if ok, err := tm.DeleteTask(3); !ok || err != nil {
+				t.Fatalf("DeleteTask(3) = %v, %v", ok, err)
+			}
+
+			expect("ListAllTasks", ids(tm.ListAllTasks()), 2)
+			expect("ListDoneTasks", ids(tm.ListDoneTasks()), []int{}...)
+			expect("SearchTasks", ids(tm.SearchTasks("report")), []int{}...)
+			expect("ListTrash", ids(tm.ListTrash()), 1, 3)
+			if _, ok, _ := tm.GetTask(1); ok {
+				t.Errorf("GetTask(1) found a trashed task")
+			}
+			if ok, err := tm.DeleteTask(1); ok || err != nil {
+				t.Errorf("DeleteTask() of a trashed task = %v, %v, want false", ok, err)
+			}
+			if ok, err := tm.MarkTaskAsTodo(1); ok || err != nil {
+				t.Errorf("MarkTaskAsTodo() of a trashed task = %v, %v, want false", ok, err)
+			}
+
+			if ok, err := tm.RestoreTask(3); !ok || err != nil {
+				t.Fatalf("RestoreTask(3) = %v, %v", ok, err)
+			}
+			if ok, err := tm.RestoreTask(2); ok || err != nil {
+				t.Errorf("RestoreTask() of an active task = %v, %v, want false", ok, err)
+			}
+			expect("ListDoneTasks after restore", ids(tm.ListDoneTasks()), 3)
+
+			if count, err := tm.EmptyTrash(time.Hour); count != 0 || err != nil {
+				t.Errorf("EmptyTrash(1h) = %d, %v, want 0 fresh tasks removed", count, err)
+			}
+			if count, err := tm.EmptyTrash(0); count != 1 || err != nil {
+				t.Errorf("EmptyTrash(0) = %d, %v, want 1", count, err)
+			}
+			expect("ListTrash after empty", ids(tm.ListTrash()), []int{}...)
+
+			id, err := tm.AddTask("New", "")
+			if err != nil || id != 4 {
+				t.Errorf("AddTask() after emptying trash = %d, %v, want id 4", id, err)
+			}
+		})
+	}
+}
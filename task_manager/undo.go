@@ -0,0 +1,245 @@
+package task_manager
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/TaskTrackerCLI/structures"
+)
+
+// DefaultHistoryLimit - сколько последних операций по умолчанию можно отменить
+const DefaultHistoryLimit = 20
+
+// undoIndexKey - ключ служебного значения в Store со стеками отмены и повтора. В стеках лежат только
+// номера операций, сами операции хранятся каждая под своим ключом (см. undoEntryKey), поэтому новая
+// операция не переписывает всю историю и не попадает в событие журнала вместе с ней
+const undoIndexKey = "undo_index"
+
+// legacyUndoHistoryKey - ключ, под которым раньше вся история отмены хранилась одним значением
+const legacyUndoHistoryKey = "undo_history"
+
+// undoEntryPrefix - префикс ключей отдельных операций истории отмены
+const undoEntryPrefix = "undo/"
+
+// undoIndex - стеки отмены и повтора, последний элемент - номер самой свежей операции
+type undoIndex struct {
+	Undo   []int64 `json:"undo"`
+	Redo   []int64 `json:"redo"`
+	NextId int64   `json:"next_id"`
+}
+
+// legacyUndoHistory - история отмены в прежнем формате, целиком в одном значении
+type legacyUndoHistory struct {
+	Undo []Operation `json:"undo"`
+	Redo []Operation `json:"redo"`
+}
+
+func undoEntryKey(id int64) string {
+	return undoEntryPrefix + strconv.FormatInt(id, 10)
+}
+
+// isUndoKey - служебное значение относится к истории отмены. Изменения истории сами в нее не попадают
+func isUndoKey(key string) bool {
+	return key == undoIndexKey || key == legacyUndoHistoryKey || strings.HasPrefix(key, undoEntryPrefix)
+}
+
+func loadUndoIndex(store Store) (undoIndex, error) {
+	var index undoIndex
+	raw, ok, err := store.Meta(undoIndexKey)
+	if err != nil {
+		return index, err
+	}
+	if !ok {
+		return migrateUndoHistory(store)
+	}
+	if err := json.Unmarshal(raw, &index); err != nil {
+		return index, fmt.Errorf("failed to read undo history: %w", err)
+	}
+	return index, nil
+}
+
+// migrateUndoHistory - раскладывает историю отмены из прежнего единого значения по отдельным операциям
+func migrateUndoHistory(store Store) (undoIndex, error) {
+	var index undoIndex
+	raw, ok, err := store.Meta(legacyUndoHistoryKey)
+	if err != nil || !ok {
+		return index, err
+	}
+	var legacy legacyUndoHistory
+	if err := json.Unmarshal(raw, &legacy); err != nil {
+		return index, fmt.Errorf("failed to read undo history: %w", err)
+	}
+	for _, stack := range []struct {
+		ops []Operation
+		ids *[]int64
+	}{{legacy.Undo, &index.Undo}, {legacy.Redo, &index.Redo}} {
+		for _, op := range stack.ops {
+			index.NextId++
+			if err := saveUndoEntry(store, index.NextId, op); err != nil {
+				return index, err
+			}
+			*stack.ids = append(*stack.ids, index.NextId)
+		}
+	}
+	if err := store.SetMeta(legacyUndoHistoryKey, nil); err != nil {
+		return index, err
+	}
+	return index, saveUndoIndex(store, index)
+}
+
+func saveUndoIndex(store Store, index undoIndex) error {
+	raw, err := json.Marshal(index)
+	if err != nil {
+		return fmt.Errorf("failed to write undo history: %w", err)
+	}
+	return store.SetMeta(undoIndexKey, raw)
+}
+
+func loadUndoEntry(store Store, id int64) (Operation, error) {
+	var op Operation
+	raw, ok, err := store.Meta(undoEntryKey(id))
+	if err != nil {
+		return op, err
+	}
+	if !ok {
+		return op, fmt.Errorf("undo history entry %d is missing", id)
+	}
+	if err := json.Unmarshal(raw, &op); err != nil {
+		return op, fmt.Errorf("failed to read undo history: %w", err)
+	}
+	return op, nil
+}
+
+func saveUndoEntry(store Store, id int64, op Operation) error {
+	raw, err := json.Marshal(op)
+	if err != nil {
+		return fmt.Errorf("failed to write undo history: %w", err)
+	}
+	return store.SetMeta(undoEntryKey(id), raw)
+}
+
+// dropUndoEntries - удаляет операции, которые больше нельзя отменить или повторить
+func dropUndoEntries(store Store, ids []int64) error {
+	for _, id := range ids {
+		if err := store.SetMeta(undoEntryKey(id), nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// pushUndo - добавляет операцию в стек отмены. Новая операция делает повтор отмененных невозможным.
+// Записываются только сама операция и стеки с номерами, старые операции не переписываются
+func (taskManager *TaskManager) pushUndo(op Operation) error {
+	if taskManager.HistoryLimit <= 0 {
+		return nil
+	}
+	store := taskManager.store
+	index, err := loadUndoIndex(store)
+	if err != nil {
+		return err
+	}
+	index.NextId++
+	if err := saveUndoEntry(store, index.NextId, op); err != nil {
+		return err
+	}
+	index.Undo = append(index.Undo, index.NextId)
+	if len(index.Undo) > taskManager.HistoryLimit {
+		evicted := len(index.Undo) - taskManager.HistoryLimit
+		if err := dropUndoEntries(store, index.Undo[:evicted]); err != nil {
+			return err
+		}
+		index.Undo = append([]int64(nil), index.Undo[evicted:]...)
+	}
+	if err := dropUndoEntries(store, index.Redo); err != nil {
+		return err
+	}
+	index.Redo = nil
+	return saveUndoIndex(store, index)
+}
+
+// Undo - отменяет последнюю операцию целиком, даже если она затронула несколько тасков.
+// Возвращает отмененную операцию и false, если отменять нечего
+func (taskManager *TaskManager) Undo() (Operation, bool, error) {
+	return taskManager.replay(OperationUndo)
+}
+
+// Redo - повторяет последнюю отмененную операцию
+func (taskManager *TaskManager) Redo() (Operation, bool, error) {
+	return taskManager.replay(OperationRedo)
+}
+
+func (taskManager *TaskManager) replay(kind string) (Operation, bool, error) {
+	var op Operation
+	var found bool
+	err := taskManager.mutate(kind, func(store Store) error {
+		index, err := loadUndoIndex(store)
+		if err != nil {
+			return err
+		}
+		from, to := &index.Undo, &index.Redo
+		if kind == OperationRedo {
+			from, to = to, from
+		}
+		if len(*from) == 0 {
+			return nil
+		}
+		id := (*from)[len(*from)-1]
+		*from = (*from)[:len(*from)-1]
+		op, err = loadUndoEntry(store, id)
+		if err != nil {
+			return err
+		}
+		found = true
+
+		if kind == OperationUndo {
+			for i := len(op.Changes) - 1; i >= 0; i-- {
+				if err := restoreTask(store, op.Changes[i].TaskId, op.Changes[i].Before); err != nil {
+					return err
+				}
+			}
+			for i := len(op.Meta) - 1; i >= 0; i-- {
+				if err := store.SetMeta(op.Meta[i].Key, op.Meta[i].Before); err != nil {
+					return err
+				}
+			}
+		} else {
+			for _, change := range op.Changes {
+				if err := restoreTask(store, change.TaskId, change.After); err != nil {
+					return err
+				}
+			}
+			for _, change := range op.Meta {
+				if err := store.SetMeta(change.Key, change.After); err != nil {
+					return err
+				}
+			}
+		}
+
+		*to = append(*to, id)
+		return saveUndoIndex(store, index)
+	})
+	if err != nil {
+		return Operation{}, false, fmt.Errorf("failed to %s: %w", kind, err)
+	}
+	return op, found, nil
+}
+
+// restoreTask - приводит таск id к состоянию state, nil означает, что таска быть не должно.
+// История таска не откатывается: сама отмена тоже попадает в нее
+func restoreTask(store Store, id int, state *structures.Task) error {
+	if state == nil {
+		return store.Delete(id)
+	}
+	task := state.Clone()
+	current, ok, err := store.Get(id)
+	if err != nil {
+		return err
+	}
+	if ok {
+		task.TaskHistory = current.TaskHistory
+	}
+	return store.Put(task)
+}
@@ -0,0 +1,193 @@
+package task_manager
+
+import (
+	"encoding/json"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/TaskTrackerCLI/structures"
+)
+
+// TestUndoRedo - проверяет отмену и повтор каждой операции, в том числе clean нескольких тасков одним шагом.
+// Каждый шаг выполняется новым TaskManager, как отдельные запуски CLI.
+func TestUndoRedo(t *testing.T) {
+	stores := map[string]func(path string) Store{
+		"JSONFileStore": func(path string) Store { return NewJSONFileStore(path + ".json") },
+		"JournalStore":  func(path string) Store { return NewJournalStore(path + ".json") },
+		"SQLiteStore":   func(path string) Store { return NewSQLiteStore(path + ".db") },
+	}
+
+	for name, newStore := range stores {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "tasks")
+			open := func() *TaskManager {
+				tm, err := NewTaskManagerWithStore(newStore(path))
+				if err != nil {
+					t.Fatalf("Failed to create TaskManager: %v", err)
+				}
+				t.Cleanup(func() { _ = tm.Close() })
+				return tm
+			}
+			names := func() []string {
+				tasks, err := open().ListAllTasks()
+				if err != nil {
+					t.Fatalf("ListAllTasks() error = %v", err)
+				}
+				result := make([]string, 0, len(tasks))
+				for _, task := range tasks {
+					result = append(result, task.TaskName+":"+task.TaskStatus)
+				}
+				return result
+			}
+			expect := func(want ...string) {
+				t.Helper()
+				got := names()
+				if len(got) != len(want) {
+					t.Fatalf("tasks = %v, want %v", got, want)
+				}
+				for i := range got {
+					if got[i] != want[i] {
+						t.Fatalf("tasks = %v, want %v", got, want)
+					}
+				}
+			}
+			undo := func(wantKind string) {
+				t.Helper()
+				op, ok, err := open().Undo()
+				if err != nil || !ok || op.Kind != wantKind {
+					t.Fatalf("Undo() = %s, %v, %v, want %s", op.Kind, ok, err, wantKind)
+				}
+			}
+
+			if _, ok, err := open().Undo(); ok || err != nil {
+				t.Fatalf("Undo() on empty history = %v, %v, want false, nil", ok, err)
+			}
+
+			for _, name := range []string{"a", "b", "c"} {
+				if _, err := open().AddTask(name, ""); err != nil {
+					t.Fatalf("AddTask() error = %v", err)
+				}
+			}
+			if _, err := open().MarkTaskAsDone(1); err != nil {
+				t.Fatalf("MarkTaskAsDone() error = %v", err)
+			}
+			if _, err := open().MarkTaskAsDone(3); err != nil {
+				t.Fatalf("MarkTaskAsDone() error = %v", err)
+			}
+			if _, err := open().UpdateTask(2, map[string]string{"task_name": "B"}); err != nil {
+				t.Fatalf("UpdateTask() error = %v", err)
+			}
+			if _, err := open().CleanDoneTasks(); err != nil {
+				t.Fatalf("CleanDoneTasks() error = %v", err)
+			}
+			expect("B:TODO")
+
+			undo(OperationClean)
+			expect("a:DONE", "B:TODO", "c:DONE")
+			undo(OperationUpdate)
+			expect("a:DONE", "b:TODO", "c:DONE")
+
+			if op, ok, err := open().Redo(); err != nil || !ok || op.Kind != OperationUpdate {
+				t.Fatalf("Redo() = %s, %v, %v, want update", op.Kind, ok, err)
+			}
+			expect("a:DONE", "B:TODO", "c:DONE")
+
+			if _, err := open().DeleteTask(2); err != nil {
+				t.Fatalf("DeleteTask() error = %v", err)
+			}
+			if _, ok, err := open().Redo(); ok || err != nil {
+				t.Fatalf("Redo() after a new operation = %v, %v, want nothing to redo", ok, err)
+			}
+			undo(OperationDelete)
+			expect("a:DONE", "B:TODO", "c:DONE")
+			undo(OperationUpdate)
+			undo(OperationStatus)
+			undo(OperationStatus)
+			undo(OperationAdd)
+			expect("a:TODO", "b:TODO")
+
+			id, err := open().AddTask("d", "")
+			if err != nil {
+				t.Fatalf("AddTask() error = %v", err)
+			}
+			if id != 4 {
+				t.Errorf("AddTask() after undoing an add returned id %d, want 4", id)
+			}
+		})
+	}
+}
+
+// TestUndoHistoryLimit - история отмены ограничена HistoryLimit последними операциями, вытесненные
+// операции удаляются из хранилища.
+func TestUndoHistoryLimit(t *testing.T) {
+	store := NewMemoryStore()
+	tm, err := NewTaskManagerWithStore(store)
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+	tm.HistoryLimit = 2
+	entries := func() int {
+		count := 0
+		for key := range store.meta {
+			if strings.HasPrefix(key, undoEntryPrefix) {
+				count++
+			}
+		}
+		return count
+	}
+
+	for i := 0; i < 5; i++ {
+		if _, err := tm.AddTask("task", ""); err != nil {
+			t.Fatalf("AddTask() error = %v", err)
+		}
+	}
+	if got := entries(); got != 2 {
+		t.Errorf("store keeps %d undo entries, want 2", got)
+	}
+	for i := 0; i < 2; i++ {
+		if _, ok, err := tm.Undo(); !ok || err != nil {
+			t.Fatalf("Undo() #%d = %v, %v", i+1, ok, err)
+		}
+	}
+	if _, ok, err := tm.Undo(); ok || err != nil {
+		t.Errorf("Undo() beyond the limit = %v, %v, want nothing to undo", ok, err)
+	}
+	if tasks, _ := tm.ListAllTasks(); len(tasks) != 3 {
+		t.Errorf("got %d tasks, want 3", len(tasks))
+	}
+}
+
+// TestUndoLegacyHistory - история отмены, сохраненная одним значением, раскладывается по отдельным
+// операциям и продолжает работать.
+func TestUndoLegacyHistory(t *testing.T) {
+	store := NewMemoryStore()
+	task := &structures.Task{TaskId: 1, TaskName: "legacy", TaskStatus: StatusTodo, TaskPriority: DefaultPriority}
+	if err := store.Put(*task); err != nil {
+		t.Fatalf("Put() error = %v", err)
+	}
+	raw, err := json.Marshal(legacyUndoHistory{Undo: []Operation{{Kind: OperationAdd, Changes: []Change{{TaskId: 1, After: task}}}}})
+	if err != nil {
+		t.Fatalf("Failed to marshal legacy history: %v", err)
+	}
+	if err := store.SetMeta(legacyUndoHistoryKey, raw); err != nil {
+		t.Fatalf("SetMeta() error = %v", err)
+	}
+
+	tm, err := NewTaskManagerWithStore(store)
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+	if op, ok, err := tm.Undo(); !ok || err != nil || op.Kind != OperationAdd {
+		t.Fatalf("Undo() = %+v, %v, %v, want the legacy add", op, ok, err)
+	}
+	if _, ok, _ := store.Get(1); ok {
+		t.Errorf("task 1 still exists after undoing its add")
+	}
+	if _, ok, _ := store.Meta(legacyUndoHistoryKey); ok {
+		t.Errorf("legacy undo history was not removed")
+	}
+	if _, ok, err := tm.Redo(); !ok || err != nil {
+		t.Errorf("Redo() = %v, %v", ok, err)
+	}
+}
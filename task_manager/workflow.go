@@ -0,0 +1,159 @@
+package task_manager
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// Статусы, на которые опирается TaskTracker: с TODO таск создается, DONE означает, что он выполнен.
+// Остальные статусы рабочего процесса считаются работой в процессе
+const (
+	StatusTodo       = "TODO"
+	StatusInProgress = "IN_PROGRESS"
+	StatusDone       = "DONE"
+)
+
+// ErrTransitionNotAllowed - рабочий процесс не разрешает такой смены статуса
+var ErrTransitionNotAllowed = errors.New("status transition is not allowed")
+
+var statusPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+// Workflow - статусы тасков и разрешенные переходы между ними
+type Workflow struct {
+	// Statuses - статусы в порядке вывода, обязательно содержат TODO и DONE
+	Statuses []string `json:"statuses"`
+	// Transitions - в какие статусы можно перейти из каждого. Если не задано, разрешены любые переходы
+	Transitions map[string][]string `json:"transitions,omitempty"`
+}
+
+// DefaultWorkflow - TODO, IN_PROGRESS и DONE, переходы между ними не ограничены
+func DefaultWorkflow() Workflow {
+	return Workflow{Statuses: []string{StatusTodo, StatusInProgress, StatusDone}}
+}
+
+// LoadWorkflow - читает рабочий процесс из json файла path. Если файла нет, используется DefaultWorkflow
+func LoadWorkflow(path string) (Workflow, error) {
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return DefaultWorkflow(), nil
+	}
+	if err != nil {
+		return Workflow{}, fmt.Errorf("failed to read workflow: %w", err)
+	}
+	var workflow Workflow
+	if err := json.Unmarshal(content, &workflow); err != nil {
+		return Workflow{}, fmt.Errorf("failed to unmarshal workflow %s: %w", path, err)
+	}
+	workflow = workflow.normalized()
+	if err := workflow.validate(); err != nil {
+		return Workflow{}, fmt.Errorf("invalid workflow %s: %w", path, err)
+	}
+	return workflow, nil
+}
+
+// NormalizeStatus - статус в каноническом виде: верхний регистр, пробелы и дефисы заменены на _
+func NormalizeStatus(status string) string {
+	status = strings.ToUpper(strings.TrimSpace(status))
+	return strings.NewReplacer(" ", "_", "-", "_").Replace(status)
+}
+
+func (workflow Workflow) normalized() Workflow {
+	result := Workflow{Statuses: make([]string, len(workflow.Statuses))}
+	for i, status := range workflow.Statuses {
+		result.Statuses[i] = NormalizeStatus(status)
+	}
+	if workflow.Transitions != nil {
+		result.Transitions = make(map[string][]string, len(workflow.Transitions))
+		for from, targets := range workflow.Transitions {
+			normalizedTargets := make([]string, len(targets))
+			for i, to := range targets {
+				normalizedTargets[i] = NormalizeStatus(to)
+			}
+			result.Transitions[NormalizeStatus(from)] = normalizedTargets
+		}
+	}
+	return result
+}
+
+func (workflow Workflow) validate() error {
+	seen := make(map[string]bool, len(workflow.Statuses))
+	for _, status := range workflow.Statuses {
+		if !statusPattern.MatchString(status) {
+			return fmt.Errorf("invalid status name %q, use letters, digits and _", status)
+		}
+		if seen[status] {
+			return fmt.Errorf("status %s is listed twice", status)
+		}
+		seen[status] = true
+	}
+	for _, required := range []string{StatusTodo, StatusDone} {
+		if !seen[required] {
+			return fmt.Errorf("statuses must include %s", required)
+		}
+	}
+	for from, targets := range workflow.Transitions {
+		if !seen[from] {
+			return fmt.Errorf("transitions from unknown status %s", from)
+		}
+		for _, to := range targets {
+			if !seen[to] {
+				return fmt.Errorf("transition %s -> %s leads to an unknown status", from, to)
+			}
+		}
+	}
+	return nil
+}
+
+// HasStatus - есть ли такой статус в рабочем процессе
+func (workflow Workflow) HasStatus(status string) bool {
+	for _, known := range workflow.Statuses {
+		if known == status {
+			return true
+		}
+	}
+	return false
+}
+
+// Next - статусы, в которые можно перевести таск из from. Таск со статусом, которого
+// больше нет в рабочем процессе, можно перевести в любой
+func (workflow Workflow) Next(from string) []string {
+	if workflow.Transitions == nil || !workflow.HasStatus(from) {
+		next := make([]string, 0, len(workflow.Statuses))
+		for _, status := range workflow.Statuses {
+			if status != from {
+				next = append(next, status)
+			}
+		}
+		return next
+	}
+	return workflow.Transitions[from]
+}
+
+// Allows - можно ли перевести таск из from в to. Повторная установка того же статуса разрешена всегда
+func (workflow Workflow) Allows(from, to string) bool {
+	if from == to {
+		return true
+	}
+	for _, next := range workflow.Next(from) {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTransition - ошибка ErrTransitionNotAllowed с подсказкой, куда можно перейти
+func (workflow Workflow) checkTransition(task int, from, to string) error {
+	if workflow.Allows(from, to) {
+		return nil
+	}
+	next := workflow.Next(from)
+	if len(next) == 0 {
+		return fmt.Errorf("%w: task %d is %s and its status cannot be changed", ErrTransitionNotAllowed, task, from)
+	}
+	return fmt.Errorf("%w: task %d is %s and can only move to %s", ErrTransitionNotAllowed, task, from, strings.Join(next, ", "))
+}
@@ -0,0 +1,124 @@
+package task_manager
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestLoadWorkflow - проверяет чтение файла рабочего процесса, приведение статусов к каноническому виду и проверку ошибок.
+func TestLoadWorkflow(t *testing.T) {
+	tests := []struct {
+		name         string
+		content      string
+		wantErr      string
+		wantStatuses []string
+	}{
+		{
+			name:         "Missing file",
+			wantStatuses: []string{StatusTodo, StatusInProgress, StatusDone},
+		},
+		{
+			name:         "Custom statuses",
+			content:      `{"statuses": ["todo", "in progress", "review", "done"], "transitions": {"todo": ["in-progress"], "Review": ["DONE"]}}`,
+			wantStatuses: []string{StatusTodo, StatusInProgress, "REVIEW", StatusDone},
+		},
+		{
+			name:    "Without DONE",
+			content: `{"statuses": ["TODO", "REVIEW"]}`,
+			wantErr: "must include DONE",
+		},
+		{
+			name:    "Duplicate status",
+			content: `{"statuses": ["TODO", "DONE", "todo"]}`,
+			wantErr: "listed twice",
+		},
+		{
+			name:    "Invalid name",
+			content: `{"statuses": ["TODO", "DONE", "ON HOLD!"]}`,
+			wantErr: "invalid status name",
+		},
+		{
+			name:    "Transition to unknown status",
+			content: `{"statuses": ["TODO", "DONE"], "transitions": {"TODO": ["REVIEW"]}}`,
+			wantErr: "unknown status",
+		},
+		{
+			name:    "Not json",
+			content: `statuses: [TODO]`,
+			wantErr: "failed to unmarshal workflow",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "workflow.json")
+			if tt.content != "" {
+				if err := os.WriteFile(path, []byte(tt.content), 0644); err != nil {
+					t.Fatalf("Failed to write workflow: %v", err)
+				}
+			}
+			workflow, err := LoadWorkflow(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("LoadWorkflow() error = %v, want error containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("LoadWorkflow() error = %v", err)
+			}
+			if strings.Join(workflow.Statuses, ",") != strings.Join(tt.wantStatuses, ",") {
+				t.Errorf("Statuses = %v, want %v", workflow.Statuses, tt.wantStatuses)
+			}
+		})
+	}
+}
+
+// TestSetStatus - проверяет, что смена статуса подчиняется переходам рабочего процесса.
+func TestSetStatus(t *testing.T) {
+	tm, err := NewTaskManagerWithStore(NewMemoryStore())
+	if err != nil {
+		t.Fatalf("Failed to create TaskManager: %v", err)
+	}
+	tm.Workflow = Workflow{
+		Statuses: []string{StatusTodo, StatusInProgress, "REVIEW", StatusDone},
+		Transitions: map[string][]string{
+			StatusTodo:       {StatusInProgress},
+			StatusInProgress: {"REVIEW", StatusTodo},
+			"REVIEW":         {StatusDone, StatusInProgress},
+		},
+	}
+	if _, err := tm.AddTask("task", ""); err != nil {
+		t.Fatalf("AddTask() error = %v", err)
+	}
+
+	steps := []struct {
+		status  string
+		wantErr error
+	}{
+		{status: "done", wantErr: ErrTransitionNotAllowed},
+		{status: "in progress"},
+		{status: "IN_PROGRESS"},
+		{status: "review"},
+		{status: StatusDone},
+		{status: StatusTodo, wantErr: ErrTransitionNotAllowed},
+	}
+	for _, step := range steps {
+		ok, _, err := tm.SetStatus(1, step.status)
+		if !errors.Is(err, step.wantErr) || (step.wantErr == nil && !ok) {
+			t.Fatalf("SetStatus(%q) = %v, %v, want error %v", step.status, ok, err, step.wantErr)
+		}
+	}
+	if _, _, err := tm.SetStatus(1, "BLOCKED"); err == nil || !strings.Contains(err.Error(), "unknown status") {
+		t.Errorf("SetStatus() with an unknown status error = %v", err)
+	}
+	if tasks, err := tm.ListTasksByStatus("review"); err != nil || len(tasks) != 0 {
+		t.Errorf("ListTasksByStatus(review) = %v, %v, want no tasks", tasks, err)
+	}
+	if tasks, err := tm.ListTasksByStatus("done"); err != nil || len(tasks) != 1 {
+		t.Errorf("ListTasksByStatus(done) = %v, %v, want one task", tasks, err)
+	}
+}
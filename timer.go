@@ -0,0 +1,179 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/TaskTrackerCLI/dates"
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var (
+	logDate       string
+	timesheetFrom string
+	timesheetTo   string
+)
+
+var startCmd = &cobra.Command{
+	Use:   "start [task_id]",
+	Short: "start a timer on a task and mark it IN_PROGRESS",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		taskID, err := strconv.Atoi(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Task ID must be an integer. %v\n", err)
+			return
+		}
+		ok, blockers, err := tm.StartTimer(taskID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error starting timer: %v\n", err)
+			return
+		}
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: Task with ID %d not found.\n", taskID)
+			return
+		}
+		fmt.Printf("⏱️ Timer started on task ID %d.\n", taskID)
+		warnIfBlocked(taskID, blockers)
+	},
+}
+
+var stopCmd = &cobra.Command{
+	Use:   "stop",
+	Short: "stop the running timer and log the time",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		timer, ok, err := tm.StopTimer()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error stopping timer: %v\n", err)
+			return
+		}
+		if !ok {
+			fmt.Println("No timer is running.")
+			return
+		}
+		fmt.Printf("⏹️ Timer stopped: %s logged on task ID %d.\n", formatDuration(timer.Elapsed(time.Now())), timer.TaskId)
+	},
+}
+
+var logCmd = &cobra.Command{
+	Use:   "log [task_id] [duration]",
+	Short: "log time spent on a task, e.g. 1h30m or 45m",
+	Args:  cobra.ExactArgs(2),
+	Run: func(cmd *cobra.Command, args []string) {
+		taskID, err := strconv.Atoi(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Task ID must be an integer. %v\n", err)
+			return
+		}
+		duration, err := time.ParseDuration(args[1])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Invalid duration '%s', use e.g. 1h30m or 45m.\n", args[1])
+			return
+		}
+		end := time.Now()
+		if logDate != "" {
+			day, err := dates.Parse(logDate, end)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return
+			}
+			// время, внесенное за прошлый день, считается закончившимся в конце рабочего дня
+			if dates.DaysUntil(day, end) != 0 {
+				end = day.Add(18 * time.Hour)
+			}
+		}
+		ok, err := tm.LogWork(taskID, duration, end)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error logging time: %v\n", err)
+			return
+		}
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: Task with ID %d not found.\n", taskID)
+			return
+		}
+		fmt.Printf("🕒 %s logged on task ID %d.\n", formatDuration(duration), taskID)
+	},
+}
+
+var timesheetCmd = &cobra.Command{
+	Use:   "timesheet",
+	Short: "summarize logged time per task and per day",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		now := time.Now()
+		from, err := dates.Parse(timesheetFrom, now)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --from: %v\n", err)
+			return
+		}
+		to, err := dates.Parse(timesheetTo, now)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: --to: %v\n", err)
+			return
+		}
+		sheet, err := tm.Timesheet(from, to)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error building timesheet: %v\n", err)
+			return
+		}
+		fmt.Printf("Timesheet %s - %s\n", dates.Format(from), dates.Format(to))
+		if sheet.Total == 0 {
+			fmt.Println("No time logged in this period.")
+		} else {
+			renderTimeTable([]string{"ID", "Task", "Time"}, len(sheet.ByTask), func(i int) []string {
+				row := sheet.ByTask[i]
+				return []string{strconv.Itoa(row.Task.TaskId), row.Task.TaskName, formatDuration(row.Duration)}
+			})
+			renderTimeTable([]string{"Day", "Time"}, len(sheet.ByDay), func(i int) []string {
+				return []string{sheet.ByDay[i].Day, formatDuration(sheet.ByDay[i].Duration)}
+			})
+			fmt.Printf("Total: %s\n", formatDuration(sheet.Total))
+		}
+		if timer, ok, err := tm.ActiveTimer(); err == nil && ok {
+			fmt.Printf("⏱️ Timer running on task ID %d for %s (not included).\n", timer.TaskId, formatDuration(timer.Elapsed(now)))
+		}
+	},
+}
+
+func renderTimeTable(header []string, rows int, row func(i int) []string) {
+	table := tablewriter.NewWriter(os.Stdout)
+	table.Header(header)
+	for i := 0; i < rows; i++ {
+		err := table.Append(row(i))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error appending row: %v\n", err)
+		}
+	}
+	err := table.Render()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error rendering table: %v\n", err)
+	}
+}
+
+// formatDuration - длительность с точностью до минуты: 1h30m, 2h, 45m
+func formatDuration(duration time.Duration) string {
+	duration = duration.Round(time.Minute)
+	hours, minutes := int(duration/time.Hour), int(duration%time.Hour/time.Minute)
+	switch {
+	case hours > 0 && minutes > 0:
+		return fmt.Sprintf("%dh%dm", hours, minutes)
+	case hours > 0:
+		return fmt.Sprintf("%dh", hours)
+	}
+	return fmt.Sprintf("%dm", minutes)
+}
+
+func init() {
+	logCmd.Flags().StringVar(&logDate, "date", "", "day the time was spent (same formats as add --due), default today")
+	timesheetCmd.Flags().StringVar(&timesheetFrom, "from", "-6d", "first day of the report (same formats as add --due)")
+	timesheetCmd.Flags().StringVar(&timesheetTo, "to", "today", "last day of the report")
+
+	mainCmd.AddCommand(startCmd)
+	mainCmd.AddCommand(stopCmd)
+	mainCmd.AddCommand(logCmd)
+	mainCmd.AddCommand(timesheetCmd)
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var emptyOlderThan string
+
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "manage deleted tasks",
+}
+
+var trashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "list tasks in the trash",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		tasks, err := tm.ListTrash()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error listing trash: %v\n", err)
+			return
+		}
+		if len(tasks) == 0 {
+			fmt.Println("Trash is empty.")
+			return
+		}
+		table := tablewriter.NewWriter(os.Stdout)
+		table.Header("ID", "Name", "Description", "Status", "Deleted")
+		for _, task := range tasks {
+			tableRow := []string{strconv.Itoa(task.TaskId), task.TaskName, task.TaskDescription, task.TaskStatus, task.TaskDeletedAt}
+			err := table.Append(tableRow)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error appending row: %v\n", err)
+			}
+		}
+		err = table.Render()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error rendering table: %v\n", err)
+		}
+		fmt.Printf("Tasks in trash (Total: %d):\n", len(tasks))
+	},
+}
+
+var trashRestoreCmd = &cobra.Command{
+	Use:   "restore [task_id]",
+	Short: "restore a task from the trash",
+	Args:  cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		taskID, err := strconv.Atoi(args[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: Task ID must be an integer. %v\n", err)
+			return
+		}
+		ok, err := tm.RestoreTask(taskID)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error restoring task: %v\n", err)
+			return
+		}
+		if !ok {
+			fmt.Fprintf(os.Stderr, "Error: Task with ID %d is not in the trash.\n", taskID)
+			return
+		}
+		fmt.Printf("♻️ Task ID %d restored.\n", taskID)
+	},
+}
+
+var trashEmptyCmd = &cobra.Command{
+	Use:   "empty",
+	Short: "permanently delete tasks from the trash",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		var olderThan time.Duration
+		if emptyOlderThan != "" {
+			var err error
+			olderThan, err = parseAge(emptyOlderThan)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				return
+			}
+		}
+		if !ConfirmAction("Are you sure you want to permanently delete tasks from the trash") {
+			fmt.Println("Operation cancelled")
+			return
+		}
+		count, err := tm.EmptyTrash(olderThan)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error emptying trash: %v\n", err)
+			return
+		}
+		fmt.Printf("Permanently deleted %d tasks.\n", count)
+	},
+}
+
+// parseAge - разбирает длительность вида 30d, 12h или 1h30m. Дни time.ParseDuration не понимает
+func parseAge(value string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n < 0 {
+			return 0, fmt.Errorf("invalid duration %q, use e.g. 30d or 12h", value)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration < 0 {
+		return 0, fmt.Errorf("invalid duration %q, use e.g. 30d or 12h", value)
+	}
+	return duration, nil
+}
+
+func init() {
+	trashEmptyCmd.Flags().StringVar(&emptyOlderThan, "older-than", "", "only delete tasks trashed longer ago than this (e.g. 30d, 12h)")
+
+	trashCmd.AddCommand(trashListCmd)
+	trashCmd.AddCommand(trashRestoreCmd)
+	trashCmd.AddCommand(trashEmptyCmd)
+	mainCmd.AddCommand(trashCmd)
+}
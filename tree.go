@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/TaskTrackerCLI/task_manager"
+	"github.com/spf13/cobra"
+)
+
+var treeCmd = &cobra.Command{
+	Use:   "tree",
+	Short: "show tasks with their subtasks and the completion of each parent",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		tree, err := tm.TaskTree()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error building task tree: %v\n", err)
+			return
+		}
+		if len(tree) == 0 {
+			fmt.Println("No tasks yet.")
+			return
+		}
+		for _, node := range tree {
+			printTreeNode(node, "", "")
+		}
+	},
+}
+
+// printTreeNode - печатает таск и его подзадачи с отступом; prefix - отступ строки таска,
+// childPrefix - отступ его подзадач
+func printTreeNode(node task_manager.TreeNode, prefix, childPrefix string) {
+	progress := ""
+	if len(node.Children) > 0 {
+		progress = fmt.Sprintf(" [%d%%]", node.Percent)
+	}
+	fmt.Printf("%s#%d %s (%s)%s\n", prefix, node.Task.TaskId, node.Task.TaskName, node.Task.TaskStatus, progress)
+	for i, child := range node.Children {
+		if i == len(node.Children)-1 {
+			printTreeNode(child, childPrefix+"└── ", childPrefix+"    ")
+		} else {
+			printTreeNode(child, childPrefix+"├── ", childPrefix+"│   ")
+		}
+	}
+}
+
+func init() {
+	mainCmd.AddCommand(treeCmd)
+}
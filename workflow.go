@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/olekukonko/tablewriter"
+	"github.com/spf13/cobra"
+)
+
+var workflowCmd = &cobra.Command{
+	Use:   "workflow",
+	Short: "show task statuses and the allowed transitions between them",
+	Args:  cobra.NoArgs,
+	Run: func(cmd *cobra.Command, args []string) {
+		table := tablewriter.NewWriter(os.Stdout)
+		table.Header("Status", "Can move to")
+		for _, status := range tm.Workflow.Statuses {
+			next := strings.Join(tm.Workflow.Next(status), ", ")
+			if next == "" {
+				next = "-"
+			}
+			err := table.Append([]string{status, next})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error appending row: %v\n", err)
+			}
+		}
+		err := table.Render()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error rendering table: %v\n", err)
+		}
+	},
+}
+
+func init() {
+	mainCmd.AddCommand(workflowCmd)
+}